@@ -0,0 +1,171 @@
+/*
+Copyright 2023.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1
+
+import (
+	"errors"
+)
+
+//+kubebuilder:object:generate=false
+
+// VaultSecretDefaults are operator-wide defaults of VaultSecretSpec, they come from the operator configuration.
+type VaultSecretDefaults struct {
+	Addr            string
+	Namespace       string
+	ReconcilePeriod string
+	SAName          string
+	SAAuthPath      string
+	Role            string
+	AppRoleAuthPath string
+	CertAuthPath    string
+	JWTAuthPath     string
+}
+
+// Default fills empty fields of the spec with defaults. It's shared by the defaulting webhook
+// and the reconciler, it fails when a default required by the spec is empty.
+func (in *VaultSecret) Default(defaults VaultSecretDefaults) error {
+	spec := &in.Spec
+
+	// the name might not be generated yet, when defaulted by the webhook
+	if spec.TargetSecretName == "" && in.Name != "" {
+		spec.TargetSecretName = in.Name
+	}
+
+	if spec.TargetFormat == "" {
+		spec.TargetFormat = "env"
+	}
+
+	if spec.ReconcilePeriod == "" {
+		spec.ReconcilePeriod = defaults.ReconcilePeriod
+	}
+
+	if spec.Addr == "" {
+		if defaults.Addr == "" {
+			return errors.New("default vault addr from app config is empty")
+		}
+
+		spec.Addr = defaults.Addr
+	}
+
+	if spec.Namespace == "" {
+		spec.Namespace = defaults.Namespace
+	}
+
+	if tls := spec.TLS; tls != nil && tls.CASecretRef != nil && tls.CASecretRef.Key == "" {
+		tls.CASecretRef.Key = "ca.crt"
+	}
+
+	if spec.Auth.Token != "" {
+		return nil
+	}
+
+	if ref := spec.Auth.TokenSecretRef; ref != nil {
+		if ref.Key == "" {
+			ref.Key = "token"
+		}
+		return nil
+	}
+
+	if spec.Auth.AppRole != nil {
+		return spec.Auth.AppRole.defaultAppRole(defaults)
+	}
+
+	if spec.Auth.Cert != nil {
+		return spec.Auth.Cert.defaultCert(defaults)
+	}
+
+	if spec.Auth.JWT != nil {
+		return spec.Auth.JWT.defaultJWT(defaults)
+	}
+
+	if spec.Auth.ServiceAccountRef == nil {
+		spec.Auth.ServiceAccountRef = &VaultSecretAuthServiceAccountRefSpec{}
+	}
+
+	if spec.Auth.ServiceAccountRef.Name == "" {
+		if defaults.SAName == "" {
+			return errors.New("default SA name from app config is empty")
+		}
+		spec.Auth.ServiceAccountRef.Name = defaults.SAName
+	}
+
+	if spec.Auth.ServiceAccountRef.AuthPath == "" {
+		if defaults.SAAuthPath == "" {
+			return errors.New("default SA auth path from app config is empty")
+		}
+
+		spec.Auth.ServiceAccountRef.AuthPath = defaults.SAAuthPath
+	}
+
+	if spec.Auth.ServiceAccountRef.Role == "" {
+		if defaults.Role != "" {
+			spec.Auth.ServiceAccountRef.Role = defaults.Role
+		} else {
+			spec.Auth.ServiceAccountRef.Role = in.Namespace
+		}
+	}
+
+	return nil
+}
+
+func (in *VaultSecretAuthAppRoleSpec) defaultAppRole(defaults VaultSecretDefaults) error {
+	if in.RoleIDKey == "" {
+		in.RoleIDKey = "role_id"
+	}
+
+	if in.SecretIDKey == "" {
+		in.SecretIDKey = "secret_id"
+	}
+
+	if in.AuthPath == "" {
+		if defaults.AppRoleAuthPath == "" {
+			return errors.New("default AppRole auth path from app config is empty")
+		}
+		in.AuthPath = defaults.AppRoleAuthPath
+	}
+
+	return nil
+}
+
+func (in *VaultSecretAuthCertSpec) defaultCert(defaults VaultSecretDefaults) error {
+	if in.AuthPath == "" {
+		if defaults.CertAuthPath == "" {
+			return errors.New("default cert auth path from app config is empty")
+		}
+		in.AuthPath = defaults.CertAuthPath
+	}
+
+	return nil
+}
+
+func (in *VaultSecretAuthJWTSpec) defaultJWT(defaults VaultSecretDefaults) error {
+	if in.ServiceAccountName == "" {
+		if defaults.SAName == "" {
+			return errors.New("default SA name from app config is empty")
+		}
+		in.ServiceAccountName = defaults.SAName
+	}
+
+	if in.AuthPath == "" {
+		if defaults.JWTAuthPath == "" {
+			return errors.New("default JWT auth path from app config is empty")
+		}
+		in.AuthPath = defaults.JWTAuthPath
+	}
+
+	return nil
+}
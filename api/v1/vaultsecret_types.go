@@ -27,15 +27,56 @@ import (
 type VaultSecretSpec struct {
 	// INSERT ADDITIONAL SPEC FIELDS - desired state of cluster
 	// Important: Run "make" to regenerate code after modifying this file
-	Addr             string              `json:"addr,omitempty" yaml:"addr"`
+	Addr string `json:"addr,omitempty" yaml:"addr"`
+	// Namespace is the Vault Enterprise namespace used for login and all reads
+	Namespace        string              `json:"namespace,omitempty" yaml:"namespace"`
 	Separator        string              `json:"separator,omitempty" yaml:"separator"`
 	Paths            []VaultSecretPath   `json:"paths" yaml:"paths"`
 	TargetSecretName string              `json:"targetSecretName,omitempty" yaml:"targetSecretName"`
 	TargetFormat     string              `json:"targetFormat,omitempty" yaml:"targetFormat"`
 	ReconcilePeriod  string              `json:"reconcilePeriod,omitempty" yaml:"reconcilePeriod,omitempty"`
 	Auth             VaultSecretAuthSpec `json:"auth,omitempty" yaml:"auth"`
+	TLS              *VaultSecretTLSSpec `json:"tls,omitempty" yaml:"tls"`
+	// Rollout restarts workloads consuming the target Secret when the synced data changes
+	Rollout *VaultSecretRolloutSpec `json:"rollout,omitempty" yaml:"rollout"`
+	// Target defines the kind of object, which is synced into, TargetSecretName is used as its name
+	Target *VaultSecretTargetSpec `json:"target,omitempty" yaml:"target"`
+	// TargetType is the type of the target Secret, defaults to Opaque
+	//+kubebuilder:validation:Enum=Opaque;kubernetes.io/tls;kubernetes.io/dockerconfigjson;kubernetes.io/basic-auth;kubernetes.io/ssh-auth
+	TargetType string `json:"targetType,omitempty" yaml:"targetType"`
+	// TargetKeys maps keys required by TargetType to keys of the synced data, e.g. "tls.crt: certificate"
+	TargetKeys map[string]string `json:"targetKeys,omitempty" yaml:"targetKeys"`
+	// Templates map keys of the target to Go templates rendered over the synced data, they replace TargetFormat layout
+	Templates map[string]string `json:"templates,omitempty" yaml:"templates"`
+	// MergeStrategy defines how keys set by more than one path are merged, paths are merged in their order.
+	// It defaults to error, which rejects the sync.
+	//+kubebuilder:validation:Enum=error;firstWins;lastWins
+	MergeStrategy string `json:"mergeStrategy,omitempty" yaml:"mergeStrategy"`
+	// DeletionPolicy defines what happens with the target Secret when the VaultSecret is deleted, defaults to Delete
+	//+kubebuilder:validation:Enum=Delete;Retain;Orphan
+	DeletionPolicy string `json:"deletionPolicy,omitempty" yaml:"deletionPolicy"`
 }
 
+// Deletion policies of VaultSecretSpec
+const (
+	// DeletionPolicyDelete deletes the target Secret together with the VaultSecret
+	DeletionPolicyDelete = "Delete"
+	// DeletionPolicyRetain keeps the target Secret, it's no longer owned nor labelled as managed by the operator
+	DeletionPolicyRetain = "Retain"
+	// DeletionPolicyOrphan keeps the target Secret, it's no longer owned by the VaultSecret
+	DeletionPolicyOrphan = "Orphan"
+)
+
+// Merge strategies of VaultSecretSpec
+const (
+	// MergeStrategyError rejects the sync when a key is set by more than one path
+	MergeStrategyError = "error"
+	// MergeStrategyFirstWins keeps the value of the first path setting the key
+	MergeStrategyFirstWins = "firstWins"
+	// MergeStrategyLastWins keeps the value of the last path setting the key
+	MergeStrategyLastWins = "lastWins"
+)
+
 func (in *VaultSecretSpec) GetSeparator() string {
 	if in.Separator == "" {
 		return "_"
@@ -43,16 +84,94 @@ func (in *VaultSecretSpec) GetSeparator() string {
 	return in.Separator
 }
 
+// GetMergeStrategy returns the strategy used for keys set by more than one path.
+func (in *VaultSecretSpec) GetMergeStrategy() string {
+	if in.MergeStrategy == "" {
+		return MergeStrategyError
+	}
+	return in.MergeStrategy
+}
+
+// GetTargetKind returns the kind of object, which is synced into.
+func (in *VaultSecretSpec) GetTargetKind() string {
+	if in.Target == nil || in.Target.Kind == "" {
+		return TargetKindSecret
+	}
+	return in.Target.Kind
+}
+
+// GetTargetType returns the type of the target Secret.
+func (in *VaultSecretSpec) GetTargetType() string {
+	if in.TargetType == "" {
+		return TargetTypeOpaque
+	}
+	return in.TargetType
+}
+
+// VaultSecretTargetSpec defines the desired state of VaultSecretTarget
+type VaultSecretTargetSpec struct {
+	//+kubebuilder:validation:Enum=Secret;ConfigMap
+	Kind string `json:"kind,omitempty" yaml:"kind"`
+}
+
+// Target kinds of VaultSecretTargetSpec
+const (
+	TargetKindSecret    = "Secret"
+	TargetKindConfigMap = "ConfigMap"
+)
+
+// Target types of VaultSecretSpec
+const (
+	TargetTypeOpaque           = "Opaque"
+	TargetTypeTLS              = "kubernetes.io/tls"
+	TargetTypeDockerConfigJSON = "kubernetes.io/dockerconfigjson"
+	TargetTypeBasicAuth        = "kubernetes.io/basic-auth"
+	TargetTypeSSHAuth          = "kubernetes.io/ssh-auth"
+)
+
 // VaultSecretAuthSpec defines the desired state of VaultSecretAuth
 type VaultSecretAuthSpec struct {
 	ServiceAccountRef *VaultSecretAuthServiceAccountRefSpec `json:"serviceAccountRef,omitempty" yaml:"serviceAccountRef"`
-	Token             string                                `json:"token,omitempty" yaml:"token"`
+	// Token is a Vault token stored in plain text, it is deprecated in favour of TokenSecretRef
+	Token          string                             `json:"token,omitempty" yaml:"token"`
+	TokenSecretRef *VaultSecretAuthTokenSecretRefSpec `json:"tokenSecretRef,omitempty" yaml:"tokenSecretRef"`
+	AppRole        *VaultSecretAuthAppRoleSpec        `json:"appRole,omitempty" yaml:"appRole"`
+	Cert           *VaultSecretAuthCertSpec           `json:"cert,omitempty" yaml:"cert"`
+	JWT            *VaultSecretAuthJWTSpec            `json:"jwt,omitempty" yaml:"jwt"`
+}
+
+// VaultSecretRolloutSpec defines the desired state of VaultSecretRollout
+type VaultSecretRolloutSpec struct {
+	Targets []VaultSecretRolloutTargetSpec `json:"targets" yaml:"targets"`
+}
+
+// VaultSecretRolloutTargetSpec defines the desired state of VaultSecretRolloutTarget
+type VaultSecretRolloutTargetSpec struct {
+	//+kubebuilder:validation:Enum=Deployment;StatefulSet;DaemonSet
+	Kind string `json:"kind" yaml:"kind"`
+	Name string `json:"name" yaml:"name"`
 }
 
 // VaultSecretPath defines the desired state of VaultSecretPath
 type VaultSecretPath struct {
 	Path   string `json:"path" yaml:"path"`
 	Prefix string `json:"prefix,omitempty" yaml:"prefix"`
+	// Version pins the KV v2 secret version, the latest version is read when empty
+	//+kubebuilder:validation:Minimum=1
+	Version int `json:"version,omitempty" yaml:"version"`
+	// Include lists glob patterns of keys to sync, all keys are synced when empty
+	Include []string `json:"include,omitempty" yaml:"include"`
+	// Exclude lists glob patterns of keys, which aren't synced
+	Exclude []string `json:"exclude,omitempty" yaml:"exclude"`
+	// KeyMap renames keys, it maps keys in Vault to keys used in the target
+	KeyMap map[string]string `json:"keyMap,omitempty" yaml:"keyMap"`
+	// Optional allows the path to be missing or empty, otherwise the sync fails.
+	// It defaults to the DEFAULT_PATHS_OPTIONAL operator setting.
+	Optional *bool `json:"optional,omitempty" yaml:"optional"`
+	// MaxDepth limits levels of sub-paths below a recursive path or matched by "**",
+	// it can only lower the MAX_PATH_DEPTH operator setting
+	//+kubebuilder:validation:Minimum=1
+	MaxDepth int `json:"maxDepth,omitempty" yaml:"maxDepth"`
 }
 
 // VaultSecretStatus defines the observed state of VaultSecret
@@ -60,6 +179,47 @@ type VaultSecretStatus struct {
 	// INSERT ADDITIONAL STATUS FIELD - define observed state of cluster
 	// Important: Run "make" to regenerate code after modifying this file
 	LastUpdated string `json:"lastUpdated" yaml:"lastUpdated"`
+	// ObservedGeneration is the generation of the VaultSecret processed by the last reconcile
+	ObservedGeneration int64 `json:"observedGeneration,omitempty" yaml:"observedGeneration"`
+	// Conditions describe the result of the last reconcile
+	//+listType=map
+	//+listMapKey=type
+	Conditions []metav1.Condition `json:"conditions,omitempty" yaml:"conditions"`
+	// Paths are the resolved Vault paths of the last sync
+	Paths []VaultSecretPathStatus `json:"paths,omitempty" yaml:"paths"`
+	// KeyCount is the number of keys in the target Secret
+	KeyCount int `json:"keyCount,omitempty" yaml:"keyCount"`
+	// ContentHash is a SHA-256 hash of the target Secret data
+	ContentHash string `json:"contentHash,omitempty" yaml:"contentHash"`
+}
+
+// Condition types of VaultSecretStatus
+const (
+	// ConditionReady is true when the target Secret is in sync with Vault
+	ConditionReady = "Ready"
+	// ConditionSynced is true when the last sync has succeeded
+	ConditionSynced = "Synced"
+	// ConditionAuthFailed is true when the last sync couldn't authenticate to Vault
+	ConditionAuthFailed = "AuthFailed"
+	// ConditionSyncRejected is true when the last sync was rejected, e.g. because of a key override
+	ConditionSyncRejected = "SyncRejected"
+	// ConditionPaused is true when syncing is paused by PausedAnnotation
+	ConditionPaused = "Paused"
+)
+
+// Annotations of VaultSecret
+const (
+	// ForceSyncAnnotation triggers a sync whenever its value changes, e.g. to the current timestamp
+	ForceSyncAnnotation = "vault.k8s.kiwi.com/force-sync"
+	// PausedAnnotation set to "true" pauses syncing of the VaultSecret
+	PausedAnnotation = "vault.k8s.kiwi.com/paused"
+)
+
+// VaultSecretPathStatus defines the observed state of VaultSecretPath
+type VaultSecretPathStatus struct {
+	Path string `json:"path" yaml:"path"`
+	// Version of the KV v2 secret that was read, empty for KV v1
+	Version int `json:"version,omitempty" yaml:"version"`
 }
 
 // VaultSecretAuthServiceAccountRefSpec defines the desired state of VaultSecretAuthTokenRef
@@ -67,10 +227,77 @@ type VaultSecretAuthServiceAccountRefSpec struct {
 	Name     string `json:"name,omitempty" yaml:"name"`
 	AuthPath string `json:"authPath,omitempty" yaml:"authPath"`
 	Role     string `json:"role,omitempty" yaml:"role"`
+	// Audiences of the Service Account token, the Kubernetes API server audience is used when empty
+	Audiences []string `json:"audiences,omitempty" yaml:"audiences"`
+	// ExpirationSeconds of the Service Account token
+	//+kubebuilder:validation:Minimum=600
+	ExpirationSeconds *int64 `json:"expirationSeconds,omitempty" yaml:"expirationSeconds"`
+}
+
+// VaultSecretAuthJWTSpec defines the desired state of VaultSecretAuthJWT
+type VaultSecretAuthJWTSpec struct {
+	// ServiceAccountName is the name of Service Account, which token is used for the login
+	ServiceAccountName string `json:"serviceAccountName,omitempty" yaml:"serviceAccountName"`
+	// Role is the name of the role in the jwt auth mount, the default role of the mount is used when empty
+	Role     string `json:"role,omitempty" yaml:"role"`
+	AuthPath string `json:"authPath,omitempty" yaml:"authPath"`
+	// Audiences of the Service Account token, they have to match bound_audiences of the role
+	Audiences []string `json:"audiences,omitempty" yaml:"audiences"`
+	// ExpirationSeconds of the Service Account token
+	//+kubebuilder:validation:Minimum=600
+	ExpirationSeconds *int64 `json:"expirationSeconds,omitempty" yaml:"expirationSeconds"`
+}
+
+// VaultSecretTLSSpec defines TLS settings of the connection to Vault
+type VaultSecretTLSSpec struct {
+	// CASecretRef references a PEM-encoded CA bundle used to verify the Vault server certificate
+	CASecretRef *VaultSecretTLSCASecretRefSpec `json:"caSecretRef,omitempty" yaml:"caSecretRef"`
+}
+
+// VaultSecretTLSCASecretRefSpec references a CA bundle stored in a Secret
+type VaultSecretTLSCASecretRefSpec struct {
+	// Name is the name of a Secret in the same namespace holding the CA bundle
+	Name string `json:"name" yaml:"name"`
+	// Key is the key of the CA bundle in the Secret, defaults to "ca.crt"
+	Key string `json:"key,omitempty" yaml:"key"`
+}
+
+// VaultSecretAuthTokenSecretRefSpec references a Vault token stored in a Secret
+type VaultSecretAuthTokenSecretRefSpec struct {
+	// Name is the name of a Secret in the same namespace holding the Vault token
+	Name string `json:"name" yaml:"name"`
+	// Key is the key of the token in the Secret, defaults to "token"
+	Key string `json:"key,omitempty" yaml:"key"`
+}
+
+// VaultSecretAuthAppRoleSpec defines the desired state of VaultSecretAuthAppRole
+type VaultSecretAuthAppRoleSpec struct {
+	// SecretName is the name of a Secret in the same namespace holding the AppRole credentials
+	SecretName string `json:"secretName" yaml:"secretName"`
+	// RoleIDKey is the key of role_id in the Secret, defaults to "role_id"
+	RoleIDKey string `json:"roleIdKey,omitempty" yaml:"roleIdKey"`
+	// SecretIDKey is the key of secret_id in the Secret, defaults to "secret_id"
+	SecretIDKey string `json:"secretIdKey,omitempty" yaml:"secretIdKey"`
+	AuthPath    string `json:"authPath,omitempty" yaml:"authPath"`
+}
+
+// VaultSecretAuthCertSpec defines the desired state of VaultSecretAuthCert
+type VaultSecretAuthCertSpec struct {
+	// SecretName is the name of a kubernetes.io/tls Secret in the same namespace holding the client certificate
+	SecretName string `json:"secretName" yaml:"secretName"`
+	// Name is the name of the certificate role, Vault tries all roles when empty
+	Name     string `json:"name,omitempty" yaml:"name"`
+	AuthPath string `json:"authPath,omitempty" yaml:"authPath"`
 }
 
 //+kubebuilder:object:root=true
 //+kubebuilder:subresource:status
+//+kubebuilder:printcolumn:name="Ready",type=string,JSONPath=`.status.conditions[?(@.type=="Ready")].status`
+//+kubebuilder:printcolumn:name="Reason",type=string,JSONPath=`.status.conditions[?(@.type=="Ready")].reason`
+//+kubebuilder:printcolumn:name="Target",type=string,JSONPath=`.spec.targetSecretName`
+//+kubebuilder:printcolumn:name="Keys",type=integer,JSONPath=`.status.keyCount`
+//+kubebuilder:printcolumn:name="Last Updated",type=string,JSONPath=`.status.lastUpdated`,priority=1
+//+kubebuilder:printcolumn:name="Age",type=date,JSONPath=`.metadata.creationTimestamp`
 
 // VaultSecret is the Schema for the vaultsecrets API
 type VaultSecret struct {
@@ -0,0 +1,184 @@
+/*
+Copyright 2023.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1
+
+import (
+	"errors"
+	"fmt"
+	"path"
+	"slices"
+	"strings"
+	"time"
+
+	"k8s.io/apimachinery/pkg/util/validation"
+)
+
+// ErrInlineTokenDeprecated is reported as a warning for VaultSecrets with Spec.Auth.Token.
+var ErrInlineTokenDeprecated = errors.New("spec.auth.token is deprecated, store the token in a Secret and use spec.auth.tokenSecretRef")
+
+var targetFormats = []string{"env", "json", "yaml", "raw"}
+
+// Validate checks the spec for errors, which don't depend on the operator configuration.
+// It's shared by the validating webhook and the reconciler.
+func (in *VaultSecretSpec) Validate() error {
+	if in.ReconcilePeriod != "" {
+		if _, err := time.ParseDuration(in.ReconcilePeriod); err != nil {
+			return fmt.Errorf("VaultSecret.Spec.ReconcilePeriod is invalid: %w", err)
+		}
+	}
+
+	if in.TargetFormat != "" && !slices.Contains(targetFormats, strings.ToLower(in.TargetFormat)) {
+		return fmt.Errorf("VaultSecret.Spec.TargetFormat %q is invalid, use one of: %s", in.TargetFormat, strings.Join(targetFormats, ", "))
+	}
+
+	if in.GetTargetType() != TargetTypeOpaque {
+		if in.GetTargetKind() != TargetKindSecret {
+			return fmt.Errorf("VaultSecret.Spec.TargetType %q can be used only with Secret targets", in.TargetType)
+		}
+		format := strings.ToLower(in.TargetFormat)
+		if len(in.Templates) == 0 && format != "" && format != "env" && format != "raw" {
+			return fmt.Errorf("VaultSecret.Spec.TargetType %q requires the env or raw target format", in.TargetType)
+		}
+	}
+
+	for key := range in.Templates {
+		if errs := validation.IsConfigMapKey(key); len(errs) > 0 {
+			return fmt.Errorf("VaultSecret.Spec.Templates key %q is invalid: %s", key, strings.Join(errs, ", "))
+		}
+	}
+
+	for _, vaultPath := range in.Paths {
+		if err := vaultPath.validate(); err != nil {
+			return err
+		}
+	}
+
+	if tls := in.TLS; tls != nil && tls.CASecretRef != nil && tls.CASecretRef.Name == "" {
+		return errors.New("VaultSecret.Spec.TLS.CASecretRef.Name is empty")
+	}
+
+	return in.Auth.validate()
+}
+
+func (in *VaultSecretPath) validate() error {
+	if in.Path == "" || in.Path == "/" {
+		return errors.New("VaultSecret.Spec.Paths contains an empty path")
+	}
+
+	if err := validatePattern(in.Path); err != nil {
+		return fmt.Errorf("VaultSecret.Spec.Paths %q: %w", in.Path, err)
+	}
+
+	if in.Version != 0 && strings.ContainsAny(in.Path, pathPatternMeta) {
+		return fmt.Errorf("VaultSecret.Spec.Paths %q: version can't be pinned for recursive paths and patterns", in.Path)
+	}
+
+	for _, pattern := range slices.Concat(in.Include, in.Exclude) {
+		if _, err := path.Match(pattern, ""); err != nil {
+			return fmt.Errorf("VaultSecret.Spec.Paths %q: pattern %q is invalid: %w", in.Path, pattern, err)
+		}
+	}
+
+	renamed := make(map[string]string, len(in.KeyMap))
+	for from, to := range in.KeyMap {
+		if to == "" {
+			return fmt.Errorf("VaultSecret.Spec.Paths %q: key %q is mapped to an empty key", in.Path, from)
+		}
+		if other, ok := renamed[to]; ok {
+			return fmt.Errorf("VaultSecret.Spec.Paths %q: keys %q and %q are both mapped to %q", in.Path, min(from, other), max(from, other), to)
+		}
+		renamed[to] = from
+	}
+
+	return nil
+}
+
+const pathPatternMeta = "*?[{"
+
+// validatePattern checks glob patterns in segments of a Vault path.
+func validatePattern(vaultPath string) error {
+	segments := strings.Split(strings.TrimPrefix(vaultPath, "/"), "/")
+	if strings.ContainsAny(segments[0], pathPatternMeta) && len(segments) > 1 {
+		return errors.New("the secrets engine mount can't be a pattern")
+	}
+
+	for _, segment := range segments {
+		if strings.Contains(segment, "**") && segment != "**" {
+			return errors.New("\"**\" has to be a whole path segment")
+		}
+		if !bracesBalanced(segment) {
+			return errors.New("braces are unbalanced or nested")
+		}
+		if _, err := path.Match(segment, ""); err != nil {
+			return fmt.Errorf("pattern %q is invalid: %w", segment, err)
+		}
+	}
+
+	return nil
+}
+
+func bracesBalanced(segment string) bool {
+	depth := 0
+	for _, r := range segment {
+		switch r {
+		case '{':
+			depth++
+		case '}':
+			depth--
+		}
+		if depth < 0 || depth > 1 {
+			return false
+		}
+	}
+	return depth == 0
+}
+
+func (in *VaultSecretAuthSpec) validate() error {
+	var methods []string
+	if in.Token != "" {
+		methods = append(methods, "Token")
+	}
+	if in.TokenSecretRef != nil {
+		methods = append(methods, "TokenSecretRef")
+	}
+	if in.ServiceAccountRef != nil {
+		methods = append(methods, "ServiceAccountRef")
+	}
+	if in.AppRole != nil {
+		methods = append(methods, "AppRole")
+	}
+	if in.Cert != nil {
+		methods = append(methods, "Cert")
+	}
+	if in.JWT != nil {
+		methods = append(methods, "JWT")
+	}
+	if len(methods) > 1 {
+		return fmt.Errorf("VaultSecret.Spec.Auth.%s are mutually exclusive", strings.Join(methods, " and VaultSecret.Spec.Auth."))
+	}
+
+	switch {
+	case in.TokenSecretRef != nil && in.TokenSecretRef.Name == "":
+		return errors.New("VaultSecret.Spec.Auth.TokenSecretRef.Name is empty")
+	case in.AppRole != nil && in.AppRole.SecretName == "":
+		return errors.New("VaultSecret.Spec.Auth.AppRole.SecretName is empty")
+	case in.Cert != nil && in.Cert.SecretName == "":
+		return errors.New("VaultSecret.Spec.Auth.Cert.SecretName is empty")
+	}
+
+	return nil
+}
@@ -0,0 +1,128 @@
+/*
+Copyright 2023.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+
+	"k8s.io/apimachinery/pkg/runtime"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+)
+
+// SetupWebhookWithManager registers webhooks of VaultSecret in the manager.
+func (in *VaultSecret) SetupWebhookWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewWebhookManagedBy(mgr).
+		For(in).
+		WithValidator(&VaultSecretValidator{}).
+		Complete()
+}
+
+// SetupDefaultingWebhookWithManager registers the defaulting webhook of VaultSecret in the manager,
+// it writes the operator defaults into the spec of stored VaultSecrets.
+func (in *VaultSecret) SetupDefaultingWebhookWithManager(mgr ctrl.Manager, defaults VaultSecretDefaults) error {
+	return ctrl.NewWebhookManagedBy(mgr).
+		For(in).
+		WithDefaulter(&VaultSecretDefaulter{Defaults: defaults}).
+		Complete()
+}
+
+// The defaulting webhook is opt-in, so it has no webhook marker. Its MutatingWebhookConfiguration
+// is in the config/defaulting-webhook kustomize component, which sets also ENABLE_DEFAULTING_WEBHOOK.
+
+//+kubebuilder:object:generate=false
+
+// VaultSecretDefaulter fills empty fields of VaultSecrets with the operator defaults.
+type VaultSecretDefaulter struct {
+	Defaults VaultSecretDefaults
+}
+
+var _ admission.CustomDefaulter = &VaultSecretDefaulter{}
+
+// Default implements admission.CustomDefaulter.
+func (d *VaultSecretDefaulter) Default(ctx context.Context, obj runtime.Object) error {
+	vaultSecret, ok := obj.(*VaultSecret)
+	if !ok {
+		return fmt.Errorf("expected a VaultSecret, got %T", obj)
+	}
+
+	// VaultSecrets being deleted must not be blocked by invalid defaults
+	if !vaultSecret.DeletionTimestamp.IsZero() {
+		return nil
+	}
+
+	// the namespace is missing in manifests applied to the current namespace
+	if vaultSecret.Namespace == "" {
+		if req, err := admission.RequestFromContext(ctx); err == nil {
+			vaultSecret.Namespace = req.Namespace
+		}
+	}
+
+	return vaultSecret.Default(d.Defaults)
+}
+
+//+kubebuilder:webhook:path=/validate-k8s-kiwi-com-v1-vaultsecret,mutating=false,failurePolicy=fail,sideEffects=None,groups=k8s.kiwi.com,resources=vaultsecrets,verbs=create;update,versions=v1,name=vvaultsecret.k8s.kiwi.com,admissionReviewVersions=v1
+
+//+kubebuilder:object:generate=false
+
+// VaultSecretValidator rejects VaultSecrets with an invalid spec.
+type VaultSecretValidator struct{}
+
+var _ admission.CustomValidator = &VaultSecretValidator{}
+
+// ValidateCreate implements admission.CustomValidator.
+func (v *VaultSecretValidator) ValidateCreate(_ context.Context, obj runtime.Object) (admission.Warnings, error) {
+	vaultSecret, ok := obj.(*VaultSecret)
+	if !ok {
+		return nil, fmt.Errorf("expected a VaultSecret, got %T", obj)
+	}
+	return validate(vaultSecret)
+}
+
+// ValidateUpdate implements admission.CustomValidator.
+func (v *VaultSecretValidator) ValidateUpdate(_ context.Context, oldObj, newObj runtime.Object) (admission.Warnings, error) {
+	oldVaultSecret, ok := oldObj.(*VaultSecret)
+	if !ok {
+		return nil, fmt.Errorf("expected a VaultSecret, got %T", oldObj)
+	}
+	vaultSecret, ok := newObj.(*VaultSecret)
+	if !ok {
+		return nil, fmt.Errorf("expected a VaultSecret, got %T", newObj)
+	}
+
+	// metadata changes (e.g. finalizers or annotations) of VaultSecrets created
+	// before the webhook was enabled must not be blocked
+	if reflect.DeepEqual(oldVaultSecret.Spec, vaultSecret.Spec) {
+		return nil, nil
+	}
+	return validate(vaultSecret)
+}
+
+// ValidateDelete implements admission.CustomValidator.
+func (v *VaultSecretValidator) ValidateDelete(context.Context, runtime.Object) (admission.Warnings, error) {
+	return nil, nil
+}
+
+func validate(vaultSecret *VaultSecret) (admission.Warnings, error) {
+	var warnings admission.Warnings
+	if vaultSecret.Spec.Auth.Token != "" {
+		warnings = append(warnings, ErrInlineTokenDeprecated.Error())
+	}
+	return warnings, vaultSecret.Spec.Validate()
+}
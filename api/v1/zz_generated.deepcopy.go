@@ -21,7 +21,8 @@ limitations under the License.
 package v1
 
 import (
-	runtime "k8s.io/apimachinery/pkg/runtime"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
 )
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
@@ -30,7 +31,7 @@ func (in *VaultSecret) DeepCopyInto(out *VaultSecret) {
 	out.TypeMeta = in.TypeMeta
 	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
 	in.Spec.DeepCopyInto(&out.Spec)
-	out.Status = in.Status
+	in.Status.DeepCopyInto(&out.Status)
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VaultSecret.
@@ -51,9 +52,74 @@ func (in *VaultSecret) DeepCopyObject() runtime.Object {
 	return nil
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VaultSecretAuthAppRoleSpec) DeepCopyInto(out *VaultSecretAuthAppRoleSpec) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VaultSecretAuthAppRoleSpec.
+func (in *VaultSecretAuthAppRoleSpec) DeepCopy() *VaultSecretAuthAppRoleSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(VaultSecretAuthAppRoleSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VaultSecretAuthCertSpec) DeepCopyInto(out *VaultSecretAuthCertSpec) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VaultSecretAuthCertSpec.
+func (in *VaultSecretAuthCertSpec) DeepCopy() *VaultSecretAuthCertSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(VaultSecretAuthCertSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VaultSecretAuthJWTSpec) DeepCopyInto(out *VaultSecretAuthJWTSpec) {
+	*out = *in
+	if in.Audiences != nil {
+		in, out := &in.Audiences, &out.Audiences
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.ExpirationSeconds != nil {
+		in, out := &in.ExpirationSeconds, &out.ExpirationSeconds
+		*out = new(int64)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VaultSecretAuthJWTSpec.
+func (in *VaultSecretAuthJWTSpec) DeepCopy() *VaultSecretAuthJWTSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(VaultSecretAuthJWTSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *VaultSecretAuthServiceAccountRefSpec) DeepCopyInto(out *VaultSecretAuthServiceAccountRefSpec) {
 	*out = *in
+	if in.Audiences != nil {
+		in, out := &in.Audiences, &out.Audiences
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.ExpirationSeconds != nil {
+		in, out := &in.ExpirationSeconds, &out.ExpirationSeconds
+		*out = new(int64)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VaultSecretAuthServiceAccountRefSpec.
@@ -72,8 +138,28 @@ func (in *VaultSecretAuthSpec) DeepCopyInto(out *VaultSecretAuthSpec) {
 	if in.ServiceAccountRef != nil {
 		in, out := &in.ServiceAccountRef, &out.ServiceAccountRef
 		*out = new(VaultSecretAuthServiceAccountRefSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.TokenSecretRef != nil {
+		in, out := &in.TokenSecretRef, &out.TokenSecretRef
+		*out = new(VaultSecretAuthTokenSecretRefSpec)
+		**out = **in
+	}
+	if in.AppRole != nil {
+		in, out := &in.AppRole, &out.AppRole
+		*out = new(VaultSecretAuthAppRoleSpec)
 		**out = **in
 	}
+	if in.Cert != nil {
+		in, out := &in.Cert, &out.Cert
+		*out = new(VaultSecretAuthCertSpec)
+		**out = **in
+	}
+	if in.JWT != nil {
+		in, out := &in.JWT, &out.JWT
+		*out = new(VaultSecretAuthJWTSpec)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VaultSecretAuthSpec.
@@ -86,6 +172,21 @@ func (in *VaultSecretAuthSpec) DeepCopy() *VaultSecretAuthSpec {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VaultSecretAuthTokenSecretRefSpec) DeepCopyInto(out *VaultSecretAuthTokenSecretRefSpec) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VaultSecretAuthTokenSecretRefSpec.
+func (in *VaultSecretAuthTokenSecretRefSpec) DeepCopy() *VaultSecretAuthTokenSecretRefSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(VaultSecretAuthTokenSecretRefSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *VaultSecretList) DeepCopyInto(out *VaultSecretList) {
 	*out = *in
@@ -121,6 +222,28 @@ func (in *VaultSecretList) DeepCopyObject() runtime.Object {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *VaultSecretPath) DeepCopyInto(out *VaultSecretPath) {
 	*out = *in
+	if in.Include != nil {
+		in, out := &in.Include, &out.Include
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Exclude != nil {
+		in, out := &in.Exclude, &out.Exclude
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.KeyMap != nil {
+		in, out := &in.KeyMap, &out.KeyMap
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.Optional != nil {
+		in, out := &in.Optional, &out.Optional
+		*out = new(bool)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VaultSecretPath.
@@ -133,15 +256,96 @@ func (in *VaultSecretPath) DeepCopy() *VaultSecretPath {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VaultSecretPathStatus) DeepCopyInto(out *VaultSecretPathStatus) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VaultSecretPathStatus.
+func (in *VaultSecretPathStatus) DeepCopy() *VaultSecretPathStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(VaultSecretPathStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VaultSecretRolloutSpec) DeepCopyInto(out *VaultSecretRolloutSpec) {
+	*out = *in
+	if in.Targets != nil {
+		in, out := &in.Targets, &out.Targets
+		*out = make([]VaultSecretRolloutTargetSpec, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VaultSecretRolloutSpec.
+func (in *VaultSecretRolloutSpec) DeepCopy() *VaultSecretRolloutSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(VaultSecretRolloutSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VaultSecretRolloutTargetSpec) DeepCopyInto(out *VaultSecretRolloutTargetSpec) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VaultSecretRolloutTargetSpec.
+func (in *VaultSecretRolloutTargetSpec) DeepCopy() *VaultSecretRolloutTargetSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(VaultSecretRolloutTargetSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *VaultSecretSpec) DeepCopyInto(out *VaultSecretSpec) {
 	*out = *in
 	if in.Paths != nil {
 		in, out := &in.Paths, &out.Paths
 		*out = make([]VaultSecretPath, len(*in))
-		copy(*out, *in)
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
 	}
 	in.Auth.DeepCopyInto(&out.Auth)
+	if in.TLS != nil {
+		in, out := &in.TLS, &out.TLS
+		*out = new(VaultSecretTLSSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Rollout != nil {
+		in, out := &in.Rollout, &out.Rollout
+		*out = new(VaultSecretRolloutSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Target != nil {
+		in, out := &in.Target, &out.Target
+		*out = new(VaultSecretTargetSpec)
+		**out = **in
+	}
+	if in.TargetKeys != nil {
+		in, out := &in.TargetKeys, &out.TargetKeys
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.Templates != nil {
+		in, out := &in.Templates, &out.Templates
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VaultSecretSpec.
@@ -157,6 +361,18 @@ func (in *VaultSecretSpec) DeepCopy() *VaultSecretSpec {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *VaultSecretStatus) DeepCopyInto(out *VaultSecretStatus) {
 	*out = *in
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]metav1.Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Paths != nil {
+		in, out := &in.Paths, &out.Paths
+		*out = make([]VaultSecretPathStatus, len(*in))
+		copy(*out, *in)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VaultSecretStatus.
@@ -168,3 +384,53 @@ func (in *VaultSecretStatus) DeepCopy() *VaultSecretStatus {
 	in.DeepCopyInto(out)
 	return out
 }
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VaultSecretTLSCASecretRefSpec) DeepCopyInto(out *VaultSecretTLSCASecretRefSpec) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VaultSecretTLSCASecretRefSpec.
+func (in *VaultSecretTLSCASecretRefSpec) DeepCopy() *VaultSecretTLSCASecretRefSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(VaultSecretTLSCASecretRefSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VaultSecretTLSSpec) DeepCopyInto(out *VaultSecretTLSSpec) {
+	*out = *in
+	if in.CASecretRef != nil {
+		in, out := &in.CASecretRef, &out.CASecretRef
+		*out = new(VaultSecretTLSCASecretRefSpec)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VaultSecretTLSSpec.
+func (in *VaultSecretTLSSpec) DeepCopy() *VaultSecretTLSSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(VaultSecretTLSSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VaultSecretTargetSpec) DeepCopyInto(out *VaultSecretTargetSpec) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VaultSecretTargetSpec.
+func (in *VaultSecretTargetSpec) DeepCopy() *VaultSecretTargetSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(VaultSecretTargetSpec)
+	in.DeepCopyInto(out)
+	return out
+}
@@ -131,12 +131,6 @@ func main() {
 		os.Exit(1)
 	}
 
-	vaultClient, err := vault.NewClient(appConfig)
-	if err != nil {
-		setupLog.Error(err, "unable to create vault client")
-		os.Exit(1)
-	}
-
 	var k8ClientSet *kubernetes.Clientset
 	kubecfg, err := config.GetConfig()
 	if err != nil {
@@ -152,11 +146,24 @@ func main() {
 		setupLog.Info("couldn't create k8s config: SA auth works only with automount")
 	}
 
-	_, err = controllers.NewVaultReconciler(mgr, appConfig, vaultClient, k8ClientSet)
+	_, err = controllers.NewVaultReconciler(mgr, appConfig, k8ClientSet)
 	if err != nil {
 		setupLog.Error(err, "unable to create reconciler controller", "controller", "VaultSecret")
 		os.Exit(1)
 	}
+	if appConfig.EnableWebhooks {
+		if err = (&k8skiwicomv1.VaultSecret{}).SetupWebhookWithManager(mgr); err != nil {
+			setupLog.Error(err, "unable to create webhook", "webhook", "VaultSecret")
+			os.Exit(1)
+		}
+		if appConfig.EnableDefaultingWebhook {
+			err = (&k8skiwicomv1.VaultSecret{}).SetupDefaultingWebhookWithManager(mgr, appConfig.VaultSecretDefaults())
+			if err != nil {
+				setupLog.Error(err, "unable to create defaulting webhook", "webhook", "VaultSecret")
+				os.Exit(1)
+			}
+		}
+	}
 	//+kubebuilder:scaffold:builder
 
 	if err := mgr.AddHealthzCheck("healthz", healthz.Ping); err != nil {
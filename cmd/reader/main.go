@@ -12,8 +12,9 @@ import (
 )
 
 var (
-	path   = flag.String("path", "", "path to VaultSecret k8s manifest")
-	output = flag.String("o", "env", "output format: env/json/yaml")
+	path     = flag.String("path", "", "path to VaultSecret k8s manifest")
+	output   = flag.String("o", "env", "output format: env/json/yaml/raw")
+	template = flag.String("template", "", "key of spec.templates to render instead of the output format")
 )
 
 func main() {
@@ -52,7 +53,11 @@ func main() {
 		stdlog.Fatal(err)
 	}
 
-	err = vaultReader.WriteData(os.Stdout, *output)
+	if len(*template) > 0 {
+		err = vaultReader.WriteTemplate(os.Stdout, *template)
+	} else {
+		err = vaultReader.WriteData(os.Stdout, *output)
+	}
 	if err != nil {
 		stdlog.Fatal(err)
 	}
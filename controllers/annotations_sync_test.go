@@ -0,0 +1,87 @@
+package controllers
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	k8skiwicomv1 "github.com/kiwicom/k8s-vault-operator/api/v1"
+)
+
+var _ = Describe("Force sync and pause annotations", func() {
+	It("should skip paused VaultSecrets and sync on force-sync", func() {
+		_, err := vaultClient.KVv2("secret").Put(ctx, "seeds/force-sync", map[string]any{"a": "1"})
+		Expect(err).ToNot(HaveOccurred())
+
+		vs := &k8skiwicomv1.VaultSecret{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "test-force-sync",
+				Namespace: namespace,
+			},
+			Spec: k8skiwicomv1.VaultSecretSpec{
+				Addr:             "http://127.0.0.1:8200",
+				TargetFormat:     "env",
+				TargetSecretName: "test-force-sync-secret",
+				ReconcilePeriod:  "1h",
+				Auth: k8skiwicomv1.VaultSecretAuthSpec{
+					Token: "testtoken",
+				},
+				Paths: []k8skiwicomv1.VaultSecretPath{
+					{Path: "secret/seeds/force-sync"},
+				},
+			},
+		}
+		Expect(k8sClient.Create(ctx, vs)).To(Succeed())
+
+		var secret corev1.Secret
+		key := types.NamespacedName{Namespace: vs.Namespace, Name: vs.Spec.TargetSecretName}
+		Eventually(func() error {
+			return k8sClient.Get(ctx, key, &secret)
+		}).Should(Succeed())
+		Expect(secret.Data).To(HaveKeyWithValue("a", []byte("1")))
+
+		_, err = vaultClient.KVv2("secret").Put(ctx, "seeds/force-sync", map[string]any{"a": "2"})
+		Expect(err).ToNot(HaveOccurred())
+
+		By("pausing the VaultSecret")
+		vsKey := types.NamespacedName{Namespace: vs.Namespace, Name: vs.Name}
+		Expect(k8sClient.Get(ctx, vsKey, vs)).To(Succeed())
+		vs.Annotations = map[string]string{k8skiwicomv1.PausedAnnotation: "true"}
+		Expect(k8sClient.Update(ctx, vs)).To(Succeed())
+		Eventually(func() bool {
+			Expect(k8sClient.Get(ctx, vsKey, vs)).To(Succeed())
+			return meta.IsStatusConditionTrue(vs.Status.Conditions, k8skiwicomv1.ConditionPaused)
+		}).Should(BeTrue())
+
+		vs.Annotations[k8skiwicomv1.ForceSyncAnnotation] = "1"
+		Expect(k8sClient.Update(ctx, vs)).To(Succeed())
+		Consistently(func() []byte {
+			Expect(k8sClient.Get(ctx, key, &secret)).To(Succeed())
+			return secret.Data["a"]
+		}).Should(Equal([]byte("1")))
+
+		By("resuming the VaultSecret")
+		Expect(k8sClient.Get(ctx, vsKey, vs)).To(Succeed())
+		delete(vs.Annotations, k8skiwicomv1.PausedAnnotation)
+		Expect(k8sClient.Update(ctx, vs)).To(Succeed())
+		Eventually(func() []byte {
+			Expect(k8sClient.Get(ctx, key, &secret)).To(Succeed())
+			return secret.Data["a"]
+		}).Should(Equal([]byte("2")))
+		Expect(k8sClient.Get(ctx, vsKey, vs)).To(Succeed())
+		Expect(meta.FindStatusCondition(vs.Status.Conditions, k8skiwicomv1.ConditionPaused)).To(BeNil())
+
+		By("forcing a sync")
+		_, err = vaultClient.KVv2("secret").Put(ctx, "seeds/force-sync", map[string]any{"a": "3"})
+		Expect(err).ToNot(HaveOccurred())
+		vs.Annotations[k8skiwicomv1.ForceSyncAnnotation] = "2"
+		Expect(k8sClient.Update(ctx, vs)).To(Succeed())
+		Eventually(func() []byte {
+			Expect(k8sClient.Get(ctx, key, &secret)).To(Succeed())
+			return secret.Data["a"]
+		}).Should(Equal([]byte("3")))
+	})
+})
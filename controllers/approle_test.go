@@ -0,0 +1,82 @@
+package controllers
+
+import (
+	vaultAPI "github.com/hashicorp/vault/api"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	k8skiwicomv1 "github.com/kiwicom/k8s-vault-operator/api/v1"
+)
+
+const readSeedsPolicy = `
+path "secret/*" {
+  capabilities = ["read", "list"]
+}
+path "v1/*" {
+  capabilities = ["read", "list"]
+}
+`
+
+var _ = Describe("AppRole authentication", func() {
+	It("should sync secrets with credentials from a referenced Secret", func() {
+		err := vaultClient.Sys().EnableAuthWithOptions("approle", &vaultAPI.EnableAuthOptions{Type: "approle"})
+		Expect(err).ToNot(HaveOccurred())
+		DeferCleanup(func() {
+			Expect(vaultClient.Sys().DisableAuth("approle")).To(Succeed())
+		})
+		Expect(vaultClient.Sys().PutPolicy("read-seeds", readSeedsPolicy)).To(Succeed())
+
+		_, err = vaultClient.Logical().Write("auth/approle/role/operator-test", map[string]any{
+			"token_policies": "read-seeds",
+			"token_ttl":      "1h",
+		})
+		Expect(err).ToNot(HaveOccurred())
+		roleID, err := vaultClient.Logical().Read("auth/approle/role/operator-test/role-id")
+		Expect(err).ToNot(HaveOccurred())
+		secretID, err := vaultClient.Logical().Write("auth/approle/role/operator-test/secret-id", nil)
+		Expect(err).ToNot(HaveOccurred())
+
+		credentials := &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "approle-credentials",
+				Namespace: namespace,
+			},
+			StringData: map[string]string{
+				"role_id":   roleID.Data["role_id"].(string),
+				"secret_id": secretID.Data["secret_id"].(string),
+			},
+		}
+		Expect(k8sClient.Create(ctx, credentials)).To(Succeed())
+
+		vs := &k8skiwicomv1.VaultSecret{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "test-approle",
+				Namespace: namespace,
+			},
+			Spec: k8skiwicomv1.VaultSecretSpec{
+				Addr:             "http://127.0.0.1:8200",
+				TargetFormat:     "env",
+				TargetSecretName: "test-approle-secret",
+				Auth: k8skiwicomv1.VaultSecretAuthSpec{
+					AppRole: &k8skiwicomv1.VaultSecretAuthAppRoleSpec{
+						SecretName: credentials.Name,
+					},
+				},
+				Paths: []k8skiwicomv1.VaultSecretPath{
+					{Path: "secret/seeds/team1/project1/secret"},
+				},
+			},
+		}
+		Expect(k8sClient.Create(ctx, vs)).To(Succeed())
+
+		var secret corev1.Secret
+		Eventually(func() error {
+			return k8sClient.Get(ctx, types.NamespacedName{Namespace: vs.Namespace, Name: vs.Spec.TargetSecretName}, &secret)
+		}).Should(Succeed())
+		Expect(secret.Data).To(HaveKeyWithValue("a", []byte("1")))
+		Expect(secret.Data).To(HaveKeyWithValue("b", []byte("10")))
+	})
+})
@@ -0,0 +1,126 @@
+package controllers
+
+import (
+	"context"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/go-logr/logr"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+
+	operatorMetrics "github.com/kiwicom/k8s-vault-operator/pkg/metrics"
+	"github.com/kiwicom/k8s-vault-operator/pkg/vault"
+)
+
+type authCacheEntry struct {
+	tokener  vault.Tokener
+	lastUsed time.Time
+	// secrets are namespaced names of Secrets the Tokener was configured from
+	secrets []types.NamespacedName
+}
+
+// authCache keeps Tokeners shared by VaultSecrets with the same auth configuration.
+// Entries, which were not used for idleTimeout, are evicted and their tokens revoked,
+// so deleted or edited VaultSecrets don't leave valid tokens behind.
+type authCache struct {
+	mx          sync.Mutex
+	entries     map[string]*authCacheEntry
+	idleTimeout time.Duration
+}
+
+func newAuthCache(idleTimeout time.Duration) *authCache {
+	return &authCache{
+		entries:     make(map[string]*authCacheEntry),
+		idleTimeout: idleTimeout,
+	}
+}
+
+// getOrSet returns the Tokener cached under id, or caches the one returned by create together with
+// the Secrets it is configured from. The lock is held during create, so concurrent reconciles don't
+// create more Tokeners for the same id and overwrite ones, which would be never revoked.
+func (c *authCache) getOrSet(id string, secrets []types.NamespacedName, create func() (vault.Tokener, error)) (vault.Tokener, error) {
+	c.mx.Lock()
+	defer c.mx.Unlock()
+
+	if entry, ok := c.entries[id]; ok {
+		entry.lastUsed = time.Now()
+		return entry.tokener, nil
+	}
+
+	tokener, err := create()
+	if err != nil {
+		return nil, err
+	}
+	c.entries[id] = &authCacheEntry{tokener: tokener, lastUsed: time.Now(), secrets: secrets}
+	operatorMetrics.AuthCacheSize.Set(float64(len(c.entries)))
+	return tokener, nil
+}
+
+// evictIdle removes entries idle for longer than idleTimeout and returns their Tokeners.
+func (c *authCache) evictIdle(now time.Time) []vault.Tokener {
+	c.mx.Lock()
+	defer c.mx.Unlock()
+
+	var evicted []vault.Tokener
+	for id, entry := range c.entries {
+		if now.Sub(entry.lastUsed) > c.idleTimeout {
+			evicted = append(evicted, entry.tokener)
+			delete(c.entries, id)
+		}
+	}
+	operatorMetrics.AuthCacheSize.Set(float64(len(c.entries)))
+
+	return evicted
+}
+
+// evictSecret removes entries configured from the Secret and returns their Tokeners.
+func (c *authCache) evictSecret(secret types.NamespacedName) []vault.Tokener {
+	c.mx.Lock()
+	defer c.mx.Unlock()
+
+	var evicted []vault.Tokener
+	for id, entry := range c.entries {
+		if slices.Contains(entry.secrets, secret) {
+			evicted = append(evicted, entry.tokener)
+			delete(c.entries, id)
+		}
+	}
+	operatorMetrics.AuthCacheSize.Set(float64(len(c.entries)))
+
+	return evicted
+}
+
+// Start periodically evicts idle entries, it implements manager.Runnable.
+func (c *authCache) Start(ctx context.Context) error {
+	if c.idleTimeout <= 0 {
+		return nil
+	}
+
+	logger := ctrl.LoggerFrom(ctx).WithName("auth-cache")
+	ticker := time.NewTicker(c.idleTimeout / 2)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case now := <-ticker.C:
+			revokeTokens(ctx, logger, c.evictIdle(now))
+		}
+	}
+}
+
+// revokeTokens revokes tokens of evicted Tokeners, which support it.
+func revokeTokens(ctx context.Context, logger logr.Logger, tokeners []vault.Tokener) {
+	for _, tokener := range tokeners {
+		revoker, ok := tokener.(vault.Revoker)
+		if !ok {
+			continue
+		}
+		if err := revoker.Revoke(ctx); err != nil {
+			logger.Error(err, "revoke evicted token")
+		}
+	}
+}
@@ -0,0 +1,87 @@
+package controllers
+
+import (
+	"errors"
+	"sync"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"k8s.io/apimachinery/pkg/types"
+
+	"github.com/kiwicom/k8s-vault-operator/pkg/vault"
+)
+
+var _ = Describe("Auth cache", func() {
+	tokener := func(token string) func() (vault.Tokener, error) {
+		return func() (vault.Tokener, error) {
+			return vault.NewAuthToken(token), nil
+		}
+	}
+
+	It("should evict only idle entries", func() {
+		cache := newAuthCache(time.Minute)
+		Expect(cache.getOrSet("idle", nil, tokener("idle"))).To(Equal(vault.NewAuthToken("idle")))
+		Expect(cache.getOrSet("used", nil, tokener("used"))).To(Equal(vault.NewAuthToken("used")))
+
+		cache.entries["idle"].lastUsed = time.Now().Add(-2 * time.Minute)
+		cache.entries["used"].lastUsed = time.Now().Add(-2 * time.Minute)
+		Expect(cache.getOrSet("used", nil, tokener("new"))).To(Equal(vault.NewAuthToken("used")))
+
+		evicted := cache.evictIdle(time.Now())
+		Expect(evicted).To(ConsistOf(vault.NewAuthToken("idle")))
+		Expect(cache.entries).ToNot(HaveKey("idle"))
+		Expect(cache.entries).To(HaveKey("used"))
+	})
+
+	It("should create a single Tokener for concurrent callers", func() {
+		cache := newAuthCache(time.Minute)
+		var (
+			wg      sync.WaitGroup
+			mx      sync.Mutex
+			created int
+			found   []vault.Tokener
+		)
+		for range 10 {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				defer GinkgoRecover()
+				tokener, err := cache.getOrSet("id", nil, func() (vault.Tokener, error) {
+					mx.Lock()
+					defer mx.Unlock()
+					created++
+					return vault.NewAuthToken("token"), nil
+				})
+				Expect(err).ToNot(HaveOccurred())
+				mx.Lock()
+				defer mx.Unlock()
+				found = append(found, tokener)
+			}()
+		}
+		wg.Wait()
+
+		Expect(created).To(Equal(1))
+		Expect(found).To(HaveLen(10))
+	})
+
+	It("should not cache a failed Tokener", func() {
+		cache := newAuthCache(time.Minute)
+		_, err := cache.getOrSet("id", nil, func() (vault.Tokener, error) {
+			return nil, errors.New("no client")
+		})
+		Expect(err).To(MatchError("no client"))
+		Expect(cache.entries).To(BeEmpty())
+	})
+	It("should evict entries configured from a Secret", func() {
+		cache := newAuthCache(time.Minute)
+		approle := types.NamespacedName{Namespace: "team", Name: "approle"}
+		Expect(cache.getOrSet("approle", []types.NamespacedName{approle}, tokener("approle"))).ToNot(BeNil())
+		Expect(cache.getOrSet("other", []types.NamespacedName{{Namespace: "other", Name: "approle"}}, tokener("other"))).ToNot(BeNil())
+		Expect(cache.getOrSet("sa", nil, tokener("sa"))).ToNot(BeNil())
+
+		Expect(cache.evictSecret(approle)).To(ConsistOf(vault.NewAuthToken("approle")))
+		Expect(cache.entries).To(HaveLen(2))
+		Expect(cache.getOrSet("approle", []types.NamespacedName{approle}, tokener("rotated"))).To(Equal(vault.NewAuthToken("rotated")))
+	})
+})
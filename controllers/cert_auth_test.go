@@ -0,0 +1,96 @@
+package controllers
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/json"
+	"encoding/pem"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+
+	"github.com/kiwicom/k8s-vault-operator/pkg/vault"
+)
+
+func selfSignedKeyPair(commonName string) ([]byte, []byte) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	Expect(err).ToNot(HaveOccurred())
+
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: commonName},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	Expect(err).ToNot(HaveOccurred())
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	Expect(err).ToNot(HaveOccurred())
+
+	return pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}),
+		pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER})
+}
+
+var _ = Describe("Cert authentication", func() {
+	It("should login with the client certificate from a TLS Secret", func() {
+		var logins atomic.Int32
+		server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.URL.Path != "/v1/auth/cert/login" || len(r.TLS.PeerCertificates) == 0 ||
+				r.TLS.PeerCertificates[0].Subject.CommonName != "operator-test" {
+				w.WriteHeader(http.StatusForbidden)
+				return
+			}
+			var body map[string]any
+			Expect(json.NewDecoder(r.Body).Decode(&body)).To(Succeed())
+			Expect(body).To(HaveKeyWithValue("name", "web"))
+
+			logins.Add(1)
+			_, _ = w.Write([]byte(`{"auth":{"client_token":"cert-token","lease_duration":3600,"renewable":true}}`))
+		}))
+		server.TLS = &tls.Config{ClientAuth: tls.RequireAnyClientCert, MinVersion: tls.VersionTLS12}
+		server.StartTLS()
+		DeferCleanup(server.Close)
+
+		certPEM, keyPEM := selfSignedKeyPair("operator-test")
+		tlsSecret := &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "test-cert-auth",
+				Namespace: namespace,
+			},
+			Type: corev1.SecretTypeTLS,
+			Data: map[string][]byte{
+				corev1.TLSCertKey:       certPEM,
+				corev1.TLSPrivateKeyKey: keyPEM,
+			},
+		}
+		Expect(k8sClient.Create(ctx, tlsSecret)).To(Succeed())
+
+		caPEM := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: server.Certificate().Raw})
+		loginClient, err := vault.NewClientForAddr(vault.AppConfig{}, server.URL, caPEM)
+		Expect(err).ToNot(HaveOccurred())
+		k8ClientSet, err := kubernetes.NewForConfig(cfg)
+		Expect(err).ToNot(HaveOccurred())
+
+		tokener := vault.NewAuthCert(loginClient, k8ClientSet, tlsSecret.Name, namespace, "web", "auth/cert/login", time.Minute)
+		for range 2 {
+			token, err := tokener.Token()
+			Expect(err).ToNot(HaveOccurred())
+			Expect(token).To(Equal("cert-token"))
+		}
+		Expect(logins.Load()).To(BeEquivalentTo(1))
+	})
+})
@@ -0,0 +1,44 @@
+package controllers
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	k8skiwicomv1 "github.com/kiwicom/k8s-vault-operator/api/v1"
+)
+
+var _ = Describe("ConfigMap target", func() {
+	It("should sync secrets into a ConfigMap", func() {
+		vs := &k8skiwicomv1.VaultSecret{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "test-configmap-target",
+				Namespace: namespace,
+			},
+			Spec: k8skiwicomv1.VaultSecretSpec{
+				Addr:         "http://127.0.0.1:8200",
+				TargetFormat: "env",
+				Target: &k8skiwicomv1.VaultSecretTargetSpec{
+					Kind: k8skiwicomv1.TargetKindConfigMap,
+				},
+				Auth: k8skiwicomv1.VaultSecretAuthSpec{
+					Token: "testtoken",
+				},
+				Paths: []k8skiwicomv1.VaultSecretPath{
+					{Path: "secret/seeds/team1/project1/secret"},
+				},
+			},
+		}
+		Expect(k8sClient.Create(ctx, vs)).To(Succeed())
+
+		var cm corev1.ConfigMap
+		key := types.NamespacedName{Namespace: vs.Namespace, Name: vs.Name}
+		Eventually(func() error {
+			return k8sClient.Get(ctx, key, &cm)
+		}).Should(Succeed())
+		Expect(cm.Data).To(HaveKeyWithValue("a", "1"))
+		Expect(cm.OwnerReferences).To(ContainElement(HaveField("Name", vs.Name)))
+	})
+})
@@ -0,0 +1,102 @@
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	k8Errors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+
+	k8skiwicomv1 "github.com/kiwicom/k8s-vault-operator/api/v1"
+	"github.com/kiwicom/k8s-vault-operator/pkg/vault"
+)
+
+// deletionPolicyFinalizer keeps the VaultSecret until the target Secret is released
+// according to its deletion policy.
+const deletionPolicyFinalizer = "vault.k8s.kiwi.com/deletion-policy"
+
+// needsFinalizer reports whether the target Secret must be released before the VaultSecret is deleted,
+// Secrets with the Delete policy are garbage collected through their owner reference.
+func needsFinalizer(vaultSecret *k8skiwicomv1.VaultSecret) bool {
+	policy := vaultSecret.Spec.DeletionPolicy
+	return policy == k8skiwicomv1.DeletionPolicyRetain || policy == k8skiwicomv1.DeletionPolicyOrphan
+}
+
+// ensureFinalizer adds or removes the finalizer according to the deletion policy.
+func (r *VaultSecretReconciler) ensureFinalizer(ctx context.Context, vaultSecret *k8skiwicomv1.VaultSecret) error {
+	hasFinalizer := controllerutil.ContainsFinalizer(vaultSecret, deletionPolicyFinalizer)
+	if needsFinalizer(vaultSecret) == hasFinalizer {
+		return nil
+	}
+
+	patch := client.MergeFrom(vaultSecret.DeepCopy())
+	if hasFinalizer {
+		controllerutil.RemoveFinalizer(vaultSecret, deletionPolicyFinalizer)
+	} else {
+		controllerutil.AddFinalizer(vaultSecret, deletionPolicyFinalizer)
+	}
+	if err := r.Client.Patch(ctx, vaultSecret, patch); err != nil {
+		return fmt.Errorf("patch finalizer: %w", err)
+	}
+	return nil
+}
+
+// finalize releases the target Secret of the deleted VaultSecret and removes the finalizer.
+func (r *VaultSecretReconciler) finalize(ctx context.Context, vaultSecret *k8skiwicomv1.VaultSecret) error {
+	if !controllerutil.ContainsFinalizer(vaultSecret, deletionPolicyFinalizer) {
+		return nil
+	}
+
+	if needsFinalizer(vaultSecret) {
+		if err := r.releaseTarget(ctx, vaultSecret); err != nil {
+			return err
+		}
+	}
+
+	patch := client.MergeFrom(vaultSecret.DeepCopy())
+	controllerutil.RemoveFinalizer(vaultSecret, deletionPolicyFinalizer)
+	if err := r.Client.Patch(ctx, vaultSecret, patch); err != nil {
+		return fmt.Errorf("patch finalizer: %w", err)
+	}
+	return nil
+}
+
+// releaseTarget removes the owner reference of the VaultSecret from the target Secret or ConfigMap,
+// so it isn't garbage collected. Targets synced with the deletion policy have no owner reference,
+// but it's removed also from targets synced before the policy was set. The Retain policy removes also the managed-by label.
+func (r *VaultSecretReconciler) releaseTarget(ctx context.Context, vaultSecret *k8skiwicomv1.VaultSecret) error {
+	name := targetName(vaultSecret)
+	kind := vaultSecret.Spec.GetTargetKind()
+	target := vault.NewTargetObject(kind)
+	if err := r.Client.Get(ctx, types.NamespacedName{Namespace: vaultSecret.Namespace, Name: name}, target); err != nil {
+		if k8Errors.IsNotFound(err) {
+			return nil
+		}
+		return fmt.Errorf("get target %s: %w", strings.ToLower(kind), err)
+	}
+
+	patch := client.MergeFrom(target.DeepCopyObject().(client.Object))
+	ownerReferences := make([]metav1.OwnerReference, 0, len(target.GetOwnerReferences()))
+	for _, ref := range target.GetOwnerReferences() {
+		if ref.UID != vaultSecret.UID {
+			ownerReferences = append(ownerReferences, ref)
+		}
+	}
+	target.SetOwnerReferences(ownerReferences)
+	if vaultSecret.Spec.DeletionPolicy == k8skiwicomv1.DeletionPolicyRetain {
+		labels := target.GetLabels()
+		delete(labels, "managed-by")
+		target.SetLabels(labels)
+	}
+	if err := r.Client.Patch(ctx, target, patch); err != nil {
+		return fmt.Errorf("release target %s: %w", strings.ToLower(kind), err)
+	}
+
+	r.EventRecorder.Normal(vaultSecret, "released", fmt.Sprintf("%s %s has been kept by the %s deletion policy.",
+		kind, target.GetName(), vaultSecret.Spec.DeletionPolicy))
+	return nil
+}
@@ -0,0 +1,115 @@
+package controllers
+
+import (
+	"strings"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	gomegaTypes "github.com/onsi/gomega/types"
+	corev1 "k8s.io/api/core/v1"
+	k8Errors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	k8skiwicomv1 "github.com/kiwicom/k8s-vault-operator/api/v1"
+	"github.com/kiwicom/k8s-vault-operator/pkg/vault"
+)
+
+var _ = Describe("Deletion policy", func() {
+	DescribeTable("should release the target Secret",
+		func(policy string, managedBy gomegaTypes.GomegaMatcher) {
+			vs := &k8skiwicomv1.VaultSecret{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "test-deletion-" + strings.ToLower(policy),
+					Namespace: namespace,
+				},
+				Spec: k8skiwicomv1.VaultSecretSpec{
+					Addr:           "http://127.0.0.1:8200",
+					TargetFormat:   "env",
+					DeletionPolicy: policy,
+					Auth: k8skiwicomv1.VaultSecretAuthSpec{
+						Token: "testtoken",
+					},
+					Paths: []k8skiwicomv1.VaultSecretPath{
+						{Path: "secret/seeds/team1/project1/secret"},
+					},
+				},
+			}
+			Expect(k8sClient.Create(ctx, vs)).To(Succeed())
+
+			var secret corev1.Secret
+			key := types.NamespacedName{Namespace: vs.Namespace, Name: vs.Name}
+			Eventually(func() error {
+				return k8sClient.Get(ctx, key, &secret)
+			}).Should(Succeed())
+			// the garbage collector would delete an owned Secret under foreground deletion
+			Expect(secret.OwnerReferences).To(BeEmpty())
+			Eventually(func() []string {
+				Expect(k8sClient.Get(ctx, key, vs)).To(Succeed())
+				return vs.Finalizers
+			}).Should(ContainElement(deletionPolicyFinalizer))
+
+			Expect(k8sClient.Delete(ctx, vs)).To(Succeed())
+			Eventually(func() bool {
+				return k8Errors.IsNotFound(k8sClient.Get(ctx, key, vs))
+			}).Should(BeTrue())
+
+			Expect(k8sClient.Get(ctx, key, &secret)).To(Succeed())
+			Expect(secret.OwnerReferences).To(BeEmpty())
+			Expect(secret.Labels).To(managedBy)
+		},
+		Entry("Retain", k8skiwicomv1.DeletionPolicyRetain, Not(HaveKey("managed-by"))),
+		Entry("Orphan", k8skiwicomv1.DeletionPolicyOrphan, HaveKeyWithValue("managed-by", vault.ManagedByLabel)),
+	)
+
+	It("should keep the target Secret under foreground deletion", func() {
+		vs := &k8skiwicomv1.VaultSecret{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "test-deletion-foreground",
+				Namespace: namespace,
+			},
+			Spec: k8skiwicomv1.VaultSecretSpec{
+				Addr:         "http://127.0.0.1:8200",
+				TargetFormat: "env",
+				Auth: k8skiwicomv1.VaultSecretAuthSpec{
+					Token: "testtoken",
+				},
+				Paths: []k8skiwicomv1.VaultSecretPath{
+					{Path: "secret/seeds/team1/project1/secret"},
+				},
+			},
+		}
+		Expect(k8sClient.Create(ctx, vs)).To(Succeed())
+
+		var secret corev1.Secret
+		key := types.NamespacedName{Namespace: vs.Namespace, Name: vs.Name}
+		Eventually(func() []metav1.OwnerReference {
+			Expect(k8sClient.Get(ctx, key, &secret)).To(Succeed())
+			return secret.OwnerReferences
+		}).ShouldNot(BeEmpty())
+
+		// the owner reference of a Secret synced before the policy was set is removed by the next sync
+		Expect(k8sClient.Get(ctx, key, vs)).To(Succeed())
+		vs.Spec.DeletionPolicy = k8skiwicomv1.DeletionPolicyRetain
+		Expect(k8sClient.Update(ctx, vs)).To(Succeed())
+		Eventually(func() []metav1.OwnerReference {
+			Expect(k8sClient.Get(ctx, key, &secret)).To(Succeed())
+			return secret.OwnerReferences
+		}).Should(BeEmpty())
+
+		Expect(k8sClient.Delete(ctx, vs, client.PropagationPolicy(metav1.DeletePropagationForeground))).To(Succeed())
+		// envtest doesn't run the garbage collector, which would remove the foregroundDeletion finalizer
+		Eventually(func() []string {
+			Expect(k8sClient.Get(ctx, key, vs)).To(Succeed())
+			return vs.Finalizers
+		}).Should(Equal([]string{metav1.FinalizerDeleteDependents}))
+
+		Expect(k8sClient.Get(ctx, key, &secret)).To(Succeed())
+		Expect(secret.OwnerReferences).To(BeEmpty())
+		Expect(secret.Labels).ToNot(HaveKey("managed-by"))
+
+		vs.Finalizers = nil
+		Expect(k8sClient.Update(ctx, vs)).To(Succeed())
+	})
+})
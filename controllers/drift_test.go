@@ -0,0 +1,95 @@
+package controllers
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+
+	k8skiwicomv1 "github.com/kiwicom/k8s-vault-operator/api/v1"
+	"github.com/kiwicom/k8s-vault-operator/pkg/vault"
+)
+
+var _ = Describe("Drift correction", func() {
+	It("should restore a modified or deleted target Secret", func() {
+		vs := &k8skiwicomv1.VaultSecret{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "test-drift",
+				Namespace: namespace,
+			},
+			Spec: k8skiwicomv1.VaultSecretSpec{
+				Addr:             "http://127.0.0.1:8200",
+				TargetFormat:     "env",
+				TargetSecretName: "test-drift-secret",
+				// long enough not to restore the Secret by a periodic sync
+				ReconcilePeriod: "1h",
+				Auth: k8skiwicomv1.VaultSecretAuthSpec{
+					Token: "testtoken",
+				},
+				Paths: []k8skiwicomv1.VaultSecretPath{
+					{Path: "secret/seeds/team1/project1/secret"},
+				},
+			},
+		}
+		Expect(k8sClient.Create(ctx, vs)).To(Succeed())
+		Eventually(func() bool {
+			Expect(k8sClient.Get(ctx, types.NamespacedName{Namespace: vs.Namespace, Name: vs.Name}, vs)).To(Succeed())
+			return meta.IsStatusConditionTrue(vs.Status.Conditions, k8skiwicomv1.ConditionReady)
+		}).Should(BeTrue())
+
+		var secret corev1.Secret
+		key := types.NamespacedName{Namespace: vs.Namespace, Name: vs.Spec.TargetSecretName}
+		Expect(k8sClient.Get(ctx, key, &secret)).To(Succeed())
+		Expect(secret.Annotations).To(HaveKeyWithValue(contentHashAnnotation, vs.Status.ContentHash))
+		secret.Data["a"] = []byte("tampered")
+		Expect(k8sClient.Update(ctx, &secret)).To(Succeed())
+
+		Eventually(func() []byte {
+			Expect(k8sClient.Get(ctx, key, &secret)).To(Succeed())
+			return secret.Data["a"]
+		}).Should(Equal([]byte("1")))
+
+		Expect(k8sClient.Delete(ctx, &secret)).To(Succeed())
+		Eventually(func() error {
+			return k8sClient.Get(ctx, key, &secret)
+		}).Should(Succeed())
+		Expect(secret.Data).To(HaveKeyWithValue("a", []byte("1")))
+	})
+})
+
+var _ = Describe("Managed Secret predicate", func() {
+	managedSecret := func(data map[string][]byte, hash string) *corev1.Secret {
+		return &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{
+				Labels:      map[string]string{"managed-by": vault.ManagedByLabel},
+				Annotations: map[string]string{contentHashAnnotation: hash},
+			},
+			Data: data,
+		}
+	}
+
+	It("should skip updates, which keep the data stamped by the operator", func() {
+		data := map[string][]byte{"a": []byte("1")}
+		old := managedSecret(map[string][]byte{"a": []byte("0")}, vault.ContentHash(map[string][]byte{"a": []byte("0")}))
+		written := managedSecret(data, vault.ContentHash(data))
+
+		Expect(managedSecretPredicate().Update(event.UpdateEvent{ObjectOld: old, ObjectNew: written})).To(BeFalse())
+	})
+
+	It("should pass updates, which modify the data", func() {
+		data := map[string][]byte{"a": []byte("1")}
+		old := managedSecret(data, vault.ContentHash(data))
+		tampered := managedSecret(map[string][]byte{"a": []byte("tampered")}, vault.ContentHash(data))
+
+		Expect(managedSecretPredicate().Update(event.UpdateEvent{ObjectOld: old, ObjectNew: tampered})).To(BeTrue())
+	})
+
+	It("should skip updates of Secrets, which aren't managed", func() {
+		secret := &corev1.Secret{Data: map[string][]byte{"a": []byte("1")}}
+
+		Expect(managedSecretPredicate().Update(event.UpdateEvent{ObjectOld: secret, ObjectNew: secret.DeepCopy()})).To(BeFalse())
+	})
+})
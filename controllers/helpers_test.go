@@ -159,6 +159,16 @@ var (
 				"valid":             "key",
 			},
 		},
+		{
+			path: "secret/seeds/team5/database",
+			data: map[string]any{
+				"host":     "db.example.com",
+				"port":     "5432",
+				"username": "app",
+				"password": "pa55",
+				"debug":    "true",
+			},
+		},
 		{
 			path: "secret/seeds/empty",
 		},
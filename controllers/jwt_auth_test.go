@@ -0,0 +1,65 @@
+package controllers
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+
+	"github.com/kiwicom/k8s-vault-operator/pkg/vault"
+)
+
+var _ = Describe("JWT authentication", func() {
+	It("should login to the jwt mount with a token for the requested audience", func() {
+		var claims struct {
+			Audience []string `json:"aud"`
+			Expiry   int64    `json:"exp"`
+		}
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			defer GinkgoRecover()
+			Expect(r.URL.Path).To(Equal("/v1/auth/jwt/login"))
+
+			var body map[string]string
+			Expect(json.NewDecoder(r.Body).Decode(&body)).To(Succeed())
+			Expect(body).To(HaveKeyWithValue("role", "my-role"))
+			parts := strings.Split(body["jwt"], ".")
+			Expect(parts).To(HaveLen(3))
+			payload, err := base64.RawURLEncoding.DecodeString(parts[1])
+			Expect(err).ToNot(HaveOccurred())
+			Expect(json.Unmarshal(payload, &claims)).To(Succeed())
+
+			_, _ = w.Write([]byte(`{"auth":{"client_token":"jwt-token","lease_duration":3600,"renewable":true}}`))
+		}))
+		DeferCleanup(server.Close)
+
+		sa := &corev1.ServiceAccount{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "test-jwt-auth",
+				Namespace: namespace,
+			},
+		}
+		Expect(k8sClient.Create(ctx, sa)).To(Succeed())
+
+		loginClient, err := vault.NewClientForAddr(vault.AppConfig{}, server.URL, nil)
+		Expect(err).ToNot(HaveOccurred())
+		k8ClientSet, err := kubernetes.NewForConfig(cfg)
+		Expect(err).ToNot(HaveOccurred())
+
+		expirationSeconds := int64(600)
+		tokener := vault.NewAuthJWT(loginClient, k8ClientSet, sa.Name, namespace, "my-role", "auth/jwt/login", time.Minute).
+			WithTokenRequest([]string{"vault"}, &expirationSeconds)
+		token, err := tokener.Token()
+		Expect(err).ToNot(HaveOccurred())
+		Expect(token).To(Equal("jwt-token"))
+		Expect(claims.Audience).To(ConsistOf("vault"))
+		Expect(time.Unix(claims.Expiry, 0)).To(BeTemporally("~", time.Now().Add(10*time.Minute), time.Minute))
+	})
+})
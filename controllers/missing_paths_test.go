@@ -0,0 +1,60 @@
+package controllers
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	k8skiwicomv1 "github.com/kiwicom/k8s-vault-operator/api/v1"
+)
+
+var _ = Describe("Required paths", func() {
+	It("should keep the Secret untouched when a required path is missing", func() {
+		vs := &k8skiwicomv1.VaultSecret{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "test-missing-paths",
+				Namespace: namespace,
+			},
+			Spec: k8skiwicomv1.VaultSecretSpec{
+				Addr:         "http://127.0.0.1:8200",
+				TargetFormat: "env",
+				Auth: k8skiwicomv1.VaultSecretAuthSpec{
+					Token: "testtoken",
+				},
+				Paths: []k8skiwicomv1.VaultSecretPath{
+					{Path: "secret/seeds/team1/project1/secret"},
+				},
+			},
+		}
+		Expect(k8sClient.Create(ctx, vs)).To(Succeed())
+
+		var secret corev1.Secret
+		key := types.NamespacedName{Namespace: vs.Namespace, Name: vs.Name}
+		Eventually(func() error {
+			return k8sClient.Get(ctx, key, &secret)
+		}).Should(Succeed())
+
+		required := false
+		Expect(k8sClient.Get(ctx, key, vs)).To(Succeed())
+		vs.Spec.Paths = []k8skiwicomv1.VaultSecretPath{
+			{Path: "secret/seeds/team1/project2/secret"},
+			{Path: "secret/seeds/team1/project2/typo", Optional: &required},
+		}
+		Expect(k8sClient.Update(ctx, vs)).To(Succeed())
+
+		Eventually(func() *metav1.Condition {
+			Expect(k8sClient.Get(ctx, key, vs)).To(Succeed())
+			return meta.FindStatusCondition(vs.Status.Conditions, k8skiwicomv1.ConditionReady)
+		}).Should(And(
+			HaveField("Status", metav1.ConditionFalse),
+			HaveField("Reason", reasonPathsMissing),
+			HaveField("Message", ContainSubstring("secret/seeds/team1/project2/typo")),
+		))
+
+		Expect(k8sClient.Get(ctx, key, &secret)).To(Succeed())
+		Expect(secret.Data).To(HaveKeyWithValue("a", []byte("1")))
+	})
+})
@@ -0,0 +1,49 @@
+package controllers
+
+import (
+	"net/http"
+	"net/http/httptest"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	k8skiwicomv1 "github.com/kiwicom/k8s-vault-operator/api/v1"
+	"github.com/kiwicom/k8s-vault-operator/pkg/vault"
+)
+
+var _ = Describe("Vault namespaces", func() {
+	It("should send the namespace of the VaultSecret on login", func() {
+		namespaces := make(chan string, 1)
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+			namespaces <- req.Header.Get("X-Vault-Namespace")
+			_, _ = w.Write([]byte(`{"auth":{"client_token":"token","lease_duration":600}}`))
+		}))
+		defer server.Close()
+
+		r := &VaultSecretReconciler{VaultConfig: vault.AppConfig{}}
+		vs := k8skiwicomv1.VaultSecret{Spec: k8skiwicomv1.VaultSecretSpec{Addr: server.URL, Namespace: "team-ns"}}
+		client, err := r.loginClient(vs, nil)
+		Expect(err).ToNot(HaveOccurred())
+
+		_, err = client.Logical().Write("auth/approle/login", map[string]any{"role_id": "role"})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(namespaces).To(Receive(Equal("team-ns")))
+
+		vs.Spec.Namespace = ""
+		client, err = r.loginClient(vs, nil)
+		Expect(err).ToNot(HaveOccurred())
+
+		_, err = client.Logical().Write("auth/approle/login", map[string]any{"role_id": "role"})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(namespaces).To(Receive(BeEmpty()))
+	})
+
+	It("should cache auth separately per namespace", func() {
+		vs := k8skiwicomv1.VaultSecret{Spec: k8skiwicomv1.VaultSecretSpec{Addr: "http://vault:8200", Namespace: "team-a"}}
+		other := *vs.DeepCopy()
+		other.Spec.Namespace = "team-b"
+
+		Expect(authCacheID(vs, nil, "approle", "creds")).ToNot(Equal(authCacheID(other, nil, "approle", "creds")))
+		Expect(authCacheID(vs, nil, "approle", "creds")).To(Equal(authCacheID(*vs.DeepCopy(), nil, "approle", "creds")))
+	})
+})
@@ -0,0 +1,43 @@
+package controllers
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	k8skiwicomv1 "github.com/kiwicom/k8s-vault-operator/api/v1"
+)
+
+var _ = Describe("Path limits", func() {
+	It("should fail the sync when a recursive path is deeper than maxDepth", func() {
+		vs := &k8skiwicomv1.VaultSecret{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "test-path-limits",
+				Namespace: namespace,
+			},
+			Spec: k8skiwicomv1.VaultSecretSpec{
+				Addr:         "http://127.0.0.1:8200",
+				TargetFormat: "env",
+				Auth: k8skiwicomv1.VaultSecretAuthSpec{
+					Token: "testtoken",
+				},
+				Paths: []k8skiwicomv1.VaultSecretPath{
+					{Path: "secret/seeds/*", MaxDepth: 1},
+				},
+			},
+		}
+		Expect(k8sClient.Create(ctx, vs)).To(Succeed())
+
+		key := types.NamespacedName{Namespace: vs.Namespace, Name: vs.Name}
+		Eventually(func() *metav1.Condition {
+			Expect(k8sClient.Get(ctx, key, vs)).To(Succeed())
+			return meta.FindStatusCondition(vs.Status.Conditions, k8skiwicomv1.ConditionReady)
+		}).Should(And(
+			HaveField("Status", metav1.ConditionFalse),
+			HaveField("Reason", reasonPathLimitExceeded),
+			HaveField("Message", ContainSubstring("deeper than 1 levels")),
+		))
+	})
+})
@@ -0,0 +1,53 @@
+package controllers
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	k8skiwicomv1 "github.com/kiwicom/k8s-vault-operator/api/v1"
+)
+
+var _ = Describe("Raw target format", func() {
+	It("should store each value verbatim in its own key", func() {
+		_, err := vaultClient.KVv2("secret").Put(ctx, "seeds/raw", map[string]any{
+			"ca.crt":      "-----BEGIN CERTIFICATE-----\nMIIB\n-----END CERTIFICATE-----\n",
+			"config.json": `{"debug": true}`,
+			"nested":      map[string]any{"key": "value"},
+			"port":        5432,
+		})
+		Expect(err).ToNot(HaveOccurred())
+
+		vs := &k8skiwicomv1.VaultSecret{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "test-raw-format",
+				Namespace: namespace,
+			},
+			Spec: k8skiwicomv1.VaultSecretSpec{
+				Addr:         "http://127.0.0.1:8200",
+				TargetFormat: "raw",
+				Auth: k8skiwicomv1.VaultSecretAuthSpec{
+					Token: "testtoken",
+				},
+				Paths: []k8skiwicomv1.VaultSecretPath{
+					{Path: "secret/seeds/raw"},
+				},
+			},
+		}
+		Expect(k8sClient.Create(ctx, vs)).To(Succeed())
+
+		var secret corev1.Secret
+		key := types.NamespacedName{Namespace: vs.Namespace, Name: vs.Name}
+		Eventually(func() error {
+			return k8sClient.Get(ctx, key, &secret)
+		}).Should(Succeed())
+		Expect(secret.Data).To(Equal(map[string][]byte{
+			"ca.crt":      []byte("-----BEGIN CERTIFICATE-----\nMIIB\n-----END CERTIFICATE-----\n"),
+			"config.json": []byte(`{"debug": true}`),
+			"nested":      []byte(`{"key":"value"}`),
+			"port":        []byte("5432"),
+		}))
+	})
+})
@@ -0,0 +1,100 @@
+package controllers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	k8Errors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	k8skiwicomv1 "github.com/kiwicom/k8s-vault-operator/api/v1"
+)
+
+// contentHashAnnotation is set on targets and on pod templates of rollout targets, so a change of
+// the synced data triggers a rolling restart of the workload.
+const contentHashAnnotation = "vault.k8s.kiwi.com/content-hash"
+
+//+kubebuilder:rbac:groups=apps,resources=deployments;statefulsets;daemonsets,verbs=get;patch
+
+// rolloutRestart stamps contentHash on pod templates of all rollout targets of the VaultSecret.
+// It's called on every sync, so targets, which failed or appeared later, are restarted too.
+// Targets, which don't exist, are skipped, failures of the others are returned together.
+func (r *VaultSecretReconciler) rolloutRestart(ctx context.Context, vaultSecret *k8skiwicomv1.VaultSecret, contentHash string) error {
+	if vaultSecret.Spec.Rollout == nil {
+		return nil
+	}
+
+	var (
+		restarted []string
+		errs      []error
+	)
+	for _, target := range vaultSecret.Spec.Rollout.Targets {
+		ok, err := r.patchPodTemplate(ctx, vaultSecret.Namespace, target, contentHash)
+		if err != nil {
+			err = fmt.Errorf("%s %s: %w", target.Kind, target.Name, err)
+			r.EventRecorder.Warning(vaultSecret, "rollout failed", err)
+			errs = append(errs, err)
+			continue
+		}
+		if ok {
+			restarted = append(restarted, target.Kind+"/"+target.Name)
+		}
+	}
+
+	if len(restarted) > 0 {
+		r.EventRecorder.Normal(vaultSecret, "rollout restarted", "Restarted "+strings.Join(restarted, ", ")+".")
+	}
+	if len(errs) > 0 {
+		return fmt.Errorf("rollout restart: %w", errors.Join(errs...))
+	}
+	return nil
+}
+
+// patchPodTemplate sets contentHash on the pod template of target, it returns false
+// if the pod template already has it or the target doesn't exist.
+func (r *VaultSecretReconciler) patchPodTemplate(ctx context.Context, namespace string,
+	target k8skiwicomv1.VaultSecretRolloutTargetSpec, contentHash string) (bool, error) {
+	var (
+		obj      client.Object
+		template *corev1.PodTemplateSpec
+	)
+	switch target.Kind {
+	case "Deployment":
+		deployment := &appsv1.Deployment{}
+		obj, template = deployment, &deployment.Spec.Template
+	case "StatefulSet":
+		statefulSet := &appsv1.StatefulSet{}
+		obj, template = statefulSet, &statefulSet.Spec.Template
+	case "DaemonSet":
+		daemonSet := &appsv1.DaemonSet{}
+		obj, template = daemonSet, &daemonSet.Spec.Template
+	default:
+		return false, fmt.Errorf("unsupported kind %q", target.Kind)
+	}
+
+	// the cached client would start informers caching all workloads of the cluster
+	if err := r.apiReader.Get(ctx, types.NamespacedName{Namespace: namespace, Name: target.Name}, obj); err != nil {
+		if k8Errors.IsNotFound(err) {
+			return false, nil
+		}
+		return false, fmt.Errorf("get: %w", err)
+	}
+	if template.Annotations[contentHashAnnotation] == contentHash {
+		return false, nil
+	}
+
+	patch := client.MergeFrom(obj.DeepCopyObject().(client.Object))
+	if template.Annotations == nil {
+		template.Annotations = make(map[string]string)
+	}
+	template.Annotations[contentHashAnnotation] = contentHash
+	if err := r.Client.Patch(ctx, obj, patch); err != nil {
+		return false, fmt.Errorf("patch: %w", err)
+	}
+	return true, nil
+}
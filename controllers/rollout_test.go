@@ -0,0 +1,127 @@
+package controllers
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	k8skiwicomv1 "github.com/kiwicom/k8s-vault-operator/api/v1"
+)
+
+var _ = Describe("Rollout restart", func() {
+	It("should stamp the content hash on the pod template of targets", func() {
+		podLabels := map[string]string{"app": "test-rollout"}
+		deployment := &appsv1.Deployment{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "test-rollout",
+				Namespace: namespace,
+			},
+			Spec: appsv1.DeploymentSpec{
+				Selector: &metav1.LabelSelector{MatchLabels: podLabels},
+				Template: corev1.PodTemplateSpec{
+					ObjectMeta: metav1.ObjectMeta{Labels: podLabels},
+					Spec: corev1.PodSpec{
+						Containers: []corev1.Container{{Name: "app", Image: "busybox"}},
+					},
+				},
+			},
+		}
+		Expect(k8sClient.Create(ctx, deployment)).To(Succeed())
+
+		vs := &k8skiwicomv1.VaultSecret{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "test-rollout",
+				Namespace: namespace,
+			},
+			Spec: k8skiwicomv1.VaultSecretSpec{
+				Addr:             "http://127.0.0.1:8200",
+				TargetFormat:     "env",
+				TargetSecretName: "test-rollout-secret",
+				Auth: k8skiwicomv1.VaultSecretAuthSpec{
+					Token: "testtoken",
+				},
+				Paths: []k8skiwicomv1.VaultSecretPath{
+					{Path: "secret/seeds/team1/project1/secret"},
+				},
+				Rollout: &k8skiwicomv1.VaultSecretRolloutSpec{
+					Targets: []k8skiwicomv1.VaultSecretRolloutTargetSpec{
+						{Kind: "Deployment", Name: deployment.Name},
+					},
+				},
+			},
+		}
+		Expect(k8sClient.Create(ctx, vs)).To(Succeed())
+		Eventually(func() bool {
+			Expect(k8sClient.Get(ctx, types.NamespacedName{Namespace: vs.Namespace, Name: vs.Name}, vs)).To(Succeed())
+			return meta.IsStatusConditionTrue(vs.Status.Conditions, k8skiwicomv1.ConditionReady)
+		}).Should(BeTrue())
+
+		Eventually(func() map[string]string {
+			Expect(k8sClient.Get(ctx, types.NamespacedName{Namespace: namespace, Name: deployment.Name}, deployment)).To(Succeed())
+			return deployment.Spec.Template.Annotations
+		}).Should(HaveKeyWithValue(contentHashAnnotation, vs.Status.ContentHash))
+	})
+
+	It("should restart a target created after the data was synced", func() {
+		vs := &k8skiwicomv1.VaultSecret{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "test-rollout-later",
+				Namespace: namespace,
+			},
+			Spec: k8skiwicomv1.VaultSecretSpec{
+				Addr:         "http://127.0.0.1:8200",
+				TargetFormat: "env",
+				Auth: k8skiwicomv1.VaultSecretAuthSpec{
+					Token: "testtoken",
+				},
+				Paths: []k8skiwicomv1.VaultSecretPath{
+					{Path: "secret/seeds/team1/project1/secret"},
+				},
+				Rollout: &k8skiwicomv1.VaultSecretRolloutSpec{
+					Targets: []k8skiwicomv1.VaultSecretRolloutTargetSpec{
+						{Kind: "Deployment", Name: "test-rollout-later"},
+					},
+				},
+			},
+		}
+		Expect(k8sClient.Create(ctx, vs)).To(Succeed())
+		key := types.NamespacedName{Namespace: vs.Namespace, Name: vs.Name}
+		// the missing target doesn't fail the sync
+		Eventually(func() bool {
+			Expect(k8sClient.Get(ctx, key, vs)).To(Succeed())
+			return meta.IsStatusConditionTrue(vs.Status.Conditions, k8skiwicomv1.ConditionReady)
+		}).Should(BeTrue())
+
+		podLabels := map[string]string{"app": "test-rollout-later"}
+		deployment := &appsv1.Deployment{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "test-rollout-later",
+				Namespace: namespace,
+			},
+			Spec: appsv1.DeploymentSpec{
+				Selector: &metav1.LabelSelector{MatchLabels: podLabels},
+				Template: corev1.PodTemplateSpec{
+					ObjectMeta: metav1.ObjectMeta{Labels: podLabels},
+					Spec: corev1.PodSpec{
+						Containers: []corev1.Container{{Name: "app", Image: "busybox"}},
+					},
+				},
+			},
+		}
+		Expect(k8sClient.Create(ctx, deployment)).To(Succeed())
+
+		// the synced data doesn't change, the next sync stamps the hash anyway
+		Expect(k8sClient.Get(ctx, key, vs)).To(Succeed())
+		vs.Annotations = map[string]string{k8skiwicomv1.ForceSyncAnnotation: "1"}
+		Expect(k8sClient.Update(ctx, vs)).To(Succeed())
+
+		Eventually(func() map[string]string {
+			Expect(k8sClient.Get(ctx, types.NamespacedName{Namespace: namespace, Name: deployment.Name}, deployment)).To(Succeed())
+			return deployment.Spec.Template.Annotations
+		}).Should(HaveKeyWithValue(contentHashAnnotation, vs.Status.ContentHash))
+	})
+})
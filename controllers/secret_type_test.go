@@ -0,0 +1,94 @@
+package controllers
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	k8skiwicomv1 "github.com/kiwicom/k8s-vault-operator/api/v1"
+)
+
+var _ = Describe("Secret types", func() {
+	It("should re-create the Secret when its type changes", func() {
+		_, err := vaultClient.KVv2("secret").Put(ctx, "seeds/tls", map[string]any{
+			"certificate": "crt",
+			"private_key": "key",
+		})
+		Expect(err).ToNot(HaveOccurred())
+
+		vs := &k8skiwicomv1.VaultSecret{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "test-secret-type",
+				Namespace: namespace,
+			},
+			Spec: k8skiwicomv1.VaultSecretSpec{
+				Addr:         "http://127.0.0.1:8200",
+				TargetFormat: "env",
+				Auth: k8skiwicomv1.VaultSecretAuthSpec{
+					Token: "testtoken",
+				},
+				Paths: []k8skiwicomv1.VaultSecretPath{
+					{Path: "secret/seeds/tls"},
+				},
+			},
+		}
+		Expect(k8sClient.Create(ctx, vs)).To(Succeed())
+
+		var secret corev1.Secret
+		key := types.NamespacedName{Namespace: vs.Namespace, Name: vs.Name}
+		Eventually(func() error {
+			return k8sClient.Get(ctx, key, &secret)
+		}).Should(Succeed())
+		Expect(secret.Type).To(Equal(corev1.SecretTypeOpaque))
+
+		Expect(k8sClient.Get(ctx, key, vs)).To(Succeed())
+		vs.Spec.TargetType = k8skiwicomv1.TargetTypeTLS
+		vs.Spec.TargetKeys = map[string]string{
+			corev1.TLSCertKey:       "certificate",
+			corev1.TLSPrivateKeyKey: "private_key",
+		}
+		Expect(k8sClient.Update(ctx, vs)).To(Succeed())
+
+		Eventually(func() corev1.SecretType {
+			Expect(k8sClient.Get(ctx, key, &secret)).To(Succeed())
+			return secret.Type
+		}).Should(Equal(corev1.SecretTypeTLS))
+		Expect(secret.Data).To(HaveKeyWithValue(corev1.TLSCertKey, []byte("crt")))
+		Expect(secret.Data).To(HaveKeyWithValue(corev1.TLSPrivateKeyKey, []byte("key")))
+		Expect(secret.Data).ToNot(HaveKey("certificate"))
+		Expect(secret.OwnerReferences).ToNot(BeEmpty())
+	})
+
+	It("should reject a sync with missing required keys", func() {
+		vs := &k8skiwicomv1.VaultSecret{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "test-secret-type-missing-keys",
+				Namespace: namespace,
+			},
+			Spec: k8skiwicomv1.VaultSecretSpec{
+				Addr:         "http://127.0.0.1:8200",
+				TargetFormat: "env",
+				TargetType:   k8skiwicomv1.TargetTypeSSHAuth,
+				Auth: k8skiwicomv1.VaultSecretAuthSpec{
+					Token: "testtoken",
+				},
+				Paths: []k8skiwicomv1.VaultSecretPath{
+					{Path: "secret/seeds/team1/project1/secret"},
+				},
+			},
+		}
+		Expect(k8sClient.Create(ctx, vs)).To(Succeed())
+
+		key := types.NamespacedName{Namespace: vs.Namespace, Name: vs.Name}
+		Eventually(func() bool {
+			Expect(k8sClient.Get(ctx, key, vs)).To(Succeed())
+			return meta.IsStatusConditionTrue(vs.Status.Conditions, k8skiwicomv1.ConditionSyncRejected)
+		}).Should(BeTrue())
+
+		var secret corev1.Secret
+		Expect(k8sClient.Get(ctx, key, &secret)).ToNot(Succeed())
+	})
+})
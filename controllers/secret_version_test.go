@@ -0,0 +1,50 @@
+package controllers
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	k8skiwicomv1 "github.com/kiwicom/k8s-vault-operator/api/v1"
+)
+
+var _ = Describe("Pinned secret version", func() {
+	It("should sync the pinned version and report it in status", func() {
+		_, err := vaultClient.KVv2("secret").Put(ctx, "seeds/pinned", map[string]any{"a": "1"})
+		Expect(err).ToNot(HaveOccurred())
+		_, err = vaultClient.KVv2("secret").Put(ctx, "seeds/pinned", map[string]any{"a": "2"})
+		Expect(err).ToNot(HaveOccurred())
+
+		vs := &k8skiwicomv1.VaultSecret{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "test-pinned-version",
+				Namespace: namespace,
+			},
+			Spec: k8skiwicomv1.VaultSecretSpec{
+				Addr:             "http://127.0.0.1:8200",
+				TargetFormat:     "env",
+				TargetSecretName: "test-pinned-version-secret",
+				Auth: k8skiwicomv1.VaultSecretAuthSpec{
+					Token: "testtoken",
+				},
+				Paths: []k8skiwicomv1.VaultSecretPath{
+					{Path: "secret/seeds/pinned", Version: 1},
+				},
+			},
+		}
+		Expect(k8sClient.Create(ctx, vs)).To(Succeed())
+
+		var secret corev1.Secret
+		Eventually(func() error {
+			return k8sClient.Get(ctx, types.NamespacedName{Namespace: vs.Namespace, Name: vs.Spec.TargetSecretName}, &secret)
+		}).Should(Succeed())
+		Expect(secret.Data).To(HaveKeyWithValue("a", []byte("1")))
+
+		Eventually(func() []k8skiwicomv1.VaultSecretPathStatus {
+			Expect(k8sClient.Get(ctx, types.NamespacedName{Namespace: vs.Namespace, Name: vs.Name}, vs)).To(Succeed())
+			return vs.Status.Paths
+		}).Should(ConsistOf(k8skiwicomv1.VaultSecretPathStatus{Path: "secret/seeds/pinned", Version: 1}))
+	})
+})
@@ -0,0 +1,90 @@
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	k8skiwicomv1 "github.com/kiwicom/k8s-vault-operator/api/v1"
+	"github.com/kiwicom/k8s-vault-operator/pkg/vault"
+)
+
+// Reasons of VaultSecret conditions
+const (
+	reasonSynced            = "Synced"
+	reasonInvalidResource   = "InvalidResource"
+	reasonTLSFailed         = "TLSFailed"
+	reasonAuthFailed        = "AuthFailed"
+	reasonAuthenticated     = "Authenticated"
+	reasonVaultFailed       = "VaultFailed"
+	reasonVaultReadFailed   = "VaultReadFailed"
+	reasonPathsMissing      = "PathsMissing"
+	reasonPathLimitExceeded = "PathLimitExceeded"
+	reasonSyncRejected      = "SyncRejected"
+	reasonSyncAccepted      = "SyncAccepted"
+	reasonSecretWriteFailed = "SecretWriteFailed"
+	reasonPaused            = "Paused"
+)
+
+func setCondition(vaultSecret *k8skiwicomv1.VaultSecret, conditionType string, status metav1.ConditionStatus, reason, message string) {
+	meta.SetStatusCondition(&vaultSecret.Status.Conditions, metav1.Condition{
+		Type:               conditionType,
+		Status:             status,
+		ObservedGeneration: vaultSecret.Generation,
+		Reason:             reason,
+		Message:            message,
+	})
+}
+
+// setSyncSucceeded records a successful sync of the target into the VaultSecret status.
+func setSyncSucceeded(vaultSecret *k8skiwicomv1.VaultSecret, target client.Object, paths []k8skiwicomv1.VaultSecretPathStatus) {
+	data := vault.ObjectData(target)
+	message := fmt.Sprintf("%s %s is in sync with Vault", vaultSecret.Spec.GetTargetKind(), target.GetName())
+	setCondition(vaultSecret, k8skiwicomv1.ConditionReady, metav1.ConditionTrue, reasonSynced, message)
+	setCondition(vaultSecret, k8skiwicomv1.ConditionSynced, metav1.ConditionTrue, reasonSynced, message)
+	setCondition(vaultSecret, k8skiwicomv1.ConditionAuthFailed, metav1.ConditionFalse, reasonAuthenticated, "")
+	setCondition(vaultSecret, k8skiwicomv1.ConditionSyncRejected, metav1.ConditionFalse, reasonSyncAccepted, "")
+
+	vaultSecret.Status.LastUpdated = metav1.Now().Format(time.RFC3339)
+	vaultSecret.Status.Paths = paths
+	vaultSecret.Status.KeyCount = len(data)
+	vaultSecret.Status.ContentHash = vault.ContentHash(data)
+}
+
+// setSyncFailed records a failed sync into the VaultSecret status,
+// details of the last successful sync are kept.
+func setSyncFailed(vaultSecret *k8skiwicomv1.VaultSecret, reason string, err error) {
+	setCondition(vaultSecret, k8skiwicomv1.ConditionReady, metav1.ConditionFalse, reason, err.Error())
+	setCondition(vaultSecret, k8skiwicomv1.ConditionSynced, metav1.ConditionFalse, reason, err.Error())
+
+	switch reason {
+	case reasonAuthFailed:
+		setCondition(vaultSecret, k8skiwicomv1.ConditionAuthFailed, metav1.ConditionTrue, reason, err.Error())
+	case reasonSyncRejected:
+		setCondition(vaultSecret, k8skiwicomv1.ConditionSyncRejected, metav1.ConditionTrue, reason, err.Error())
+	}
+}
+
+// syncFailed writes a failed sync into the VaultSecret status, errors are only logged,
+// so they don't hide the original error.
+func (r *VaultSecretReconciler) syncFailed(ctx context.Context, vaultSecret *k8skiwicomv1.VaultSecret, reason string, err error) {
+	setSyncFailed(vaultSecret, reason, err)
+	if err := updateVaultSecretStatus(ctx, r.Client, vaultSecret); err != nil {
+		ctrl.LoggerFrom(ctx).Error(err, "update status")
+	}
+}
+
+func updateVaultSecretStatus(ctx context.Context, c client.Client, vaultSecret *k8skiwicomv1.VaultSecret) error {
+	// only status is updated here
+	vaultSecret.Status.ObservedGeneration = vaultSecret.Generation
+	if err := c.Status().Update(ctx, vaultSecret); err != nil {
+		return fmt.Errorf("failed to update resource status: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,90 @@
+package controllers
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	k8skiwicomv1 "github.com/kiwicom/k8s-vault-operator/api/v1"
+	"github.com/kiwicom/k8s-vault-operator/pkg/vault"
+)
+
+var _ = Describe("VaultSecret status", func() {
+	getStatus := func(vs *k8skiwicomv1.VaultSecret) func() k8skiwicomv1.VaultSecretStatus {
+		return func() k8skiwicomv1.VaultSecretStatus {
+			Expect(k8sClient.Get(ctx, types.NamespacedName{Namespace: vs.Namespace, Name: vs.Name}, vs)).To(Succeed())
+			return vs.Status
+		}
+	}
+
+	It("should report a successful sync", func() {
+		vs := &k8skiwicomv1.VaultSecret{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "test-status-synced",
+				Namespace: namespace,
+			},
+			Spec: k8skiwicomv1.VaultSecretSpec{
+				Addr:             "http://127.0.0.1:8200",
+				TargetFormat:     "env",
+				TargetSecretName: "test-status-synced-secret",
+				Auth: k8skiwicomv1.VaultSecretAuthSpec{
+					Token: "testtoken",
+				},
+				Paths: []k8skiwicomv1.VaultSecretPath{
+					{Path: "secret/seeds/team1/project1/secret"},
+				},
+			},
+		}
+		Expect(k8sClient.Create(ctx, vs)).To(Succeed())
+
+		Eventually(func() bool {
+			return meta.IsStatusConditionTrue(getStatus(vs)().Conditions, k8skiwicomv1.ConditionReady)
+		}).Should(BeTrue())
+
+		var secret corev1.Secret
+		Expect(k8sClient.Get(ctx, types.NamespacedName{Namespace: vs.Namespace, Name: vs.Spec.TargetSecretName}, &secret)).To(Succeed())
+		Expect(vs.Status.ObservedGeneration).To(Equal(vs.Generation))
+		Expect(vs.Status.KeyCount).To(Equal(len(secret.Data)))
+		Expect(vs.Status.ContentHash).To(Equal(vault.ContentHash(secret.Data)))
+		Expect(vs.Status.Paths).To(ConsistOf(HaveField("Path", "secret/seeds/team1/project1/secret")))
+		Expect(meta.IsStatusConditionTrue(vs.Status.Conditions, k8skiwicomv1.ConditionSynced)).To(BeTrue())
+		Expect(meta.IsStatusConditionFalse(vs.Status.Conditions, k8skiwicomv1.ConditionAuthFailed)).To(BeTrue())
+		Expect(meta.IsStatusConditionFalse(vs.Status.Conditions, k8skiwicomv1.ConditionSyncRejected)).To(BeTrue())
+	})
+
+	It("should report a failed authentication", func() {
+		vs := &k8skiwicomv1.VaultSecret{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "test-status-auth-failed",
+				Namespace: namespace,
+			},
+			Spec: k8skiwicomv1.VaultSecretSpec{
+				Addr:             "http://127.0.0.1:8200",
+				TargetFormat:     "env",
+				TargetSecretName: "test-status-auth-failed-secret",
+				Auth: k8skiwicomv1.VaultSecretAuthSpec{
+					TokenSecretRef: &k8skiwicomv1.VaultSecretAuthTokenSecretRefSpec{
+						Name: "missing-token",
+					},
+				},
+				Paths: []k8skiwicomv1.VaultSecretPath{
+					{Path: "secret/seeds/team1/project1/secret"},
+				},
+			},
+		}
+		Expect(k8sClient.Create(ctx, vs)).To(Succeed())
+
+		Eventually(func() bool {
+			return meta.IsStatusConditionTrue(getStatus(vs)().Conditions, k8skiwicomv1.ConditionAuthFailed)
+		}).Should(BeTrue())
+
+		ready := meta.FindStatusCondition(vs.Status.Conditions, k8skiwicomv1.ConditionReady)
+		Expect(ready).ToNot(BeNil())
+		Expect(ready.Status).To(Equal(metav1.ConditionFalse))
+		Expect(ready.Reason).To(Equal(reasonAuthFailed))
+		Expect(vs.Status.LastUpdated).To(BeEmpty())
+	})
+})
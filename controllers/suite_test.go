@@ -19,12 +19,16 @@ package controllers
 import (
 	"bytes"
 	"context"
+	"crypto/tls"
 	"encoding/json"
+	"fmt"
 	"io"
+	"net"
 	"os"
 	"path/filepath"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/hashicorp/go-envparse"
 	vaultAPI "github.com/hashicorp/vault/api"
@@ -41,6 +45,7 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/envtest"
 	logf "sigs.k8s.io/controller-runtime/pkg/log"
 	"sigs.k8s.io/controller-runtime/pkg/log/zap"
+	"sigs.k8s.io/controller-runtime/pkg/webhook"
 
 	k8skiwicomv1 "github.com/kiwicom/k8s-vault-operator/api/v1"
 	"github.com/kiwicom/k8s-vault-operator/pkg/vault"
@@ -82,6 +87,9 @@ var _ = BeforeSuite(func() {
 	testEnv = &envtest.Environment{
 		CRDDirectoryPaths:     []string{filepath.Join("..", "config", "crd", "bases")},
 		ErrorIfCRDPathMissing: true,
+		WebhookInstallOptions: envtest.WebhookInstallOptions{
+			Paths: []string{filepath.Join("..", "config", "webhook"), filepath.Join("..", "config", "defaulting-webhook")},
+		},
 	}
 
 	var err error
@@ -108,22 +116,33 @@ var _ = BeforeSuite(func() {
 	logger := zap.New(zap.UseFlagOptions(&opts))
 	ctrl.SetLogger(logger)
 
+	webhookInstallOptions := &testEnv.WebhookInstallOptions
 	k8sManager, err := ctrl.NewManager(cfg, ctrl.Options{
 		Scheme: scheme.Scheme,
 		Logger: logger,
+		WebhookServer: webhook.NewServer(webhook.Options{
+			Host:    webhookInstallOptions.LocalServingHost,
+			Port:    webhookInstallOptions.LocalServingPort,
+			CertDir: webhookInstallOptions.LocalServingCertDir,
+		}),
 	})
 	Expect(err).ToNot(HaveOccurred())
+	err = (&k8skiwicomv1.VaultSecret{}).SetupWebhookWithManager(k8sManager)
+	Expect(err).ToNot(HaveOccurred())
 
 	appConfig, err := vault.NewAppConfig()
 	Expect(err).ToNot(HaveOccurred())
 
+	err = (&k8skiwicomv1.VaultSecret{}).SetupDefaultingWebhookWithManager(k8sManager, appConfig.VaultSecretDefaults())
+	Expect(err).ToNot(HaveOccurred())
+
 	k8ClientSet, err := kubernetes.NewForConfig(k8sManager.GetConfig())
 	Expect(err).ToNot(HaveOccurred())
 
 	vaultClient, err = vault.NewClient(appConfig)
 	Expect(err).ToNot(HaveOccurred())
 	vaultClient.SetToken("testtoken")
-	_, err = NewVaultReconciler(k8sManager, appConfig, vaultClient, k8ClientSet)
+	_, err = NewVaultReconciler(k8sManager, appConfig, k8ClientSet)
 	Expect(err).ToNot(HaveOccurred())
 
 	err = vaultClient.Sys().Mount("v1", &vaultAPI.MountInput{
@@ -154,6 +173,18 @@ var _ = BeforeSuite(func() {
 		Expect(err).ToNot(HaveOccurred(), "failed to run manager")
 	}()
 
+	// wait for the webhook server to get ready
+	dialer := &net.Dialer{Timeout: time.Second}
+	addrPort := fmt.Sprintf("%s:%d", webhookInstallOptions.LocalServingHost, webhookInstallOptions.LocalServingPort)
+	Eventually(func() error {
+		//nolint:gosec
+		conn, err := tls.DialWithDialer(dialer, "tcp", addrPort, &tls.Config{InsecureSkipVerify: true})
+		if err != nil {
+			return err
+		}
+		return conn.Close()
+	}).Should(Succeed())
+
 })
 
 var _ = AfterSuite(func() {
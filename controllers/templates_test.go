@@ -0,0 +1,81 @@
+package controllers
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	k8skiwicomv1 "github.com/kiwicom/k8s-vault-operator/api/v1"
+)
+
+var _ = Describe("Templates", func() {
+	It("should render templates into the Secret", func() {
+		vs := &k8skiwicomv1.VaultSecret{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "test-templates",
+				Namespace: namespace,
+			},
+			Spec: k8skiwicomv1.VaultSecretSpec{
+				Addr:         "http://127.0.0.1:8200",
+				TargetFormat: "env",
+				Templates: map[string]string{
+					"DATABASE_URL":           "postgres://user:{{ .a }}@db:{{ index . \"port\" | default 5432 }}/{{ .b }}",
+					"application.properties": "{{ range $key := keys . }}{{ $key }}={{ index $ $key }}\n{{ end }}",
+					"encoded":                "{{ .a | b64enc }}",
+				},
+				Auth: k8skiwicomv1.VaultSecretAuthSpec{
+					Token: "testtoken",
+				},
+				Paths: []k8skiwicomv1.VaultSecretPath{
+					{Path: "secret/seeds/team1/project1/secret"},
+				},
+			},
+		}
+		Expect(k8sClient.Create(ctx, vs)).To(Succeed())
+
+		var secret corev1.Secret
+		key := types.NamespacedName{Namespace: vs.Namespace, Name: vs.Name}
+		Eventually(func() error {
+			return k8sClient.Get(ctx, key, &secret)
+		}).Should(Succeed())
+		Expect(secret.Data).To(Equal(map[string][]byte{
+			"DATABASE_URL":           []byte("postgres://user:1@db:5432/10"),
+			"application.properties": []byte("a=1\nb=10\n"),
+			"encoded":                []byte("MQ=="),
+		}))
+	})
+
+	It("should reject a sync with a failing template", func() {
+		vs := &k8skiwicomv1.VaultSecret{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "test-templates-required",
+				Namespace: namespace,
+			},
+			Spec: k8skiwicomv1.VaultSecretSpec{
+				Addr:         "http://127.0.0.1:8200",
+				TargetFormat: "env",
+				Templates: map[string]string{
+					"password": `{{ required "password is missing" (index . "password") }}`,
+				},
+				Auth: k8skiwicomv1.VaultSecretAuthSpec{
+					Token: "testtoken",
+				},
+				Paths: []k8skiwicomv1.VaultSecretPath{
+					{Path: "secret/seeds/team1/project1/secret"},
+				},
+			},
+		}
+		Expect(k8sClient.Create(ctx, vs)).To(Succeed())
+
+		key := types.NamespacedName{Namespace: vs.Namespace, Name: vs.Name}
+		Eventually(func() bool {
+			Expect(k8sClient.Get(ctx, key, vs)).To(Succeed())
+			return meta.IsStatusConditionTrue(vs.Status.Conditions, k8skiwicomv1.ConditionSyncRejected)
+		}).Should(BeTrue())
+		Expect(meta.FindStatusCondition(vs.Status.Conditions, k8skiwicomv1.ConditionSyncRejected).Message).
+			To(ContainSubstring("password is missing"))
+	})
+})
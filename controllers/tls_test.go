@@ -0,0 +1,75 @@
+package controllers
+
+import (
+	"encoding/pem"
+	"net/http/httptest"
+	"net/http/httputil"
+	"net/url"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	k8skiwicomv1 "github.com/kiwicom/k8s-vault-operator/api/v1"
+)
+
+var _ = Describe("Custom CA", func() {
+	It("should re-sync once the referenced CA is updated", func() {
+		vaultURL, err := url.Parse("http://127.0.0.1:8200")
+		Expect(err).ToNot(HaveOccurred())
+		// Vault of the suite behind a TLS proxy with a certificate signed by an unknown CA
+		proxy := httptest.NewTLSServer(httputil.NewSingleHostReverseProxy(vaultURL))
+		defer proxy.Close()
+
+		wrongCA, _ := selfSignedKeyPair("wrong-ca")
+		caSecret := &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "test-custom-ca-bundle",
+				Namespace: namespace,
+			},
+			Data: map[string][]byte{
+				"ca.crt": wrongCA,
+			},
+		}
+		Expect(k8sClient.Create(ctx, caSecret)).To(Succeed())
+
+		vs := &k8skiwicomv1.VaultSecret{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "test-custom-ca",
+				Namespace: namespace,
+			},
+			Spec: k8skiwicomv1.VaultSecretSpec{
+				Addr:         proxy.URL,
+				TargetFormat: "env",
+				Auth: k8skiwicomv1.VaultSecretAuthSpec{
+					Token: "testtoken",
+				},
+				TLS: &k8skiwicomv1.VaultSecretTLSSpec{
+					CASecretRef: &k8skiwicomv1.VaultSecretTLSCASecretRefSpec{Name: caSecret.Name},
+				},
+				Paths: []k8skiwicomv1.VaultSecretPath{
+					{Path: "secret/seeds/team1/project1/secret"},
+				},
+			},
+		}
+		Expect(k8sClient.Create(ctx, vs)).To(Succeed())
+
+		var secret corev1.Secret
+		key := types.NamespacedName{Namespace: vs.Namespace, Name: vs.Name}
+		Consistently(func() error {
+			return k8sClient.Get(ctx, key, &secret)
+		}).ShouldNot(Succeed())
+
+		caSecret.Data = map[string][]byte{
+			"ca.crt": pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: proxy.Certificate().Raw}),
+		}
+		Expect(k8sClient.Update(ctx, caSecret)).To(Succeed())
+
+		Eventually(func() error {
+			return k8sClient.Get(ctx, key, &secret)
+		}).Should(Succeed())
+		Expect(secret.Data).To(HaveKeyWithValue("a", []byte("1")))
+	})
+})
@@ -0,0 +1,64 @@
+package controllers
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	k8skiwicomv1 "github.com/kiwicom/k8s-vault-operator/api/v1"
+)
+
+var _ = Describe("Token from a Secret", func() {
+	It("should re-sync once the referenced token is rotated", func() {
+		tokenSecret := &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "test-token-ref-token",
+				Namespace: namespace,
+			},
+			StringData: map[string]string{
+				"vault-token": "invalid-token",
+			},
+		}
+		Expect(k8sClient.Create(ctx, tokenSecret)).To(Succeed())
+
+		vs := &k8skiwicomv1.VaultSecret{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "test-token-ref",
+				Namespace: namespace,
+			},
+			Spec: k8skiwicomv1.VaultSecretSpec{
+				Addr:             "http://127.0.0.1:8200",
+				TargetFormat:     "env",
+				TargetSecretName: "test-token-ref-secret",
+				Auth: k8skiwicomv1.VaultSecretAuthSpec{
+					TokenSecretRef: &k8skiwicomv1.VaultSecretAuthTokenSecretRefSpec{
+						Name: tokenSecret.Name,
+						Key:  "vault-token",
+					},
+				},
+				Paths: []k8skiwicomv1.VaultSecretPath{
+					{Path: "secret/seeds/team1/project1/secret"},
+				},
+			},
+		}
+		Expect(k8sClient.Create(ctx, vs)).To(Succeed())
+
+		var secret corev1.Secret
+		key := types.NamespacedName{Namespace: vs.Namespace, Name: vs.Spec.TargetSecretName}
+		Consistently(func() error {
+			return k8sClient.Get(ctx, key, &secret)
+		}).ShouldNot(Succeed())
+
+		tokenSecret.StringData = map[string]string{
+			"vault-token": "testtoken",
+		}
+		Expect(k8sClient.Update(ctx, tokenSecret)).To(Succeed())
+
+		Eventually(func() error {
+			return k8sClient.Get(ctx, key, &secret)
+		}).Should(Succeed())
+		Expect(secret.Data).To(HaveKeyWithValue("a", []byte("1")))
+	})
+})
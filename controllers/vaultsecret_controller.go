@@ -18,24 +18,29 @@ package controllers
 
 import (
 	"context"
+	"crypto/sha256"
 	"errors"
 	"fmt"
 	"reflect"
+	"strconv"
 	"strings"
-	"sync"
 	"time"
 
 	vaultAPI "github.com/hashicorp/vault/api"
 	corev1 "k8s.io/api/core/v1"
 	k8Errors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/client-go/kubernetes"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/controller"
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
 	"sigs.k8s.io/controller-runtime/pkg/manager"
 	"sigs.k8s.io/controller-runtime/pkg/predicate"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
@@ -45,39 +50,50 @@ import (
 	"github.com/kiwicom/k8s-vault-operator/pkg/vault"
 )
 
+const (
+	referencedSecretsIndex = ".spec.referencedSecrets"
+	targetIndex            = ".spec.target"
+)
+
 // VaultSecretReconciler reconciles a VaultSecret object
 type VaultSecretReconciler struct {
 	Client        client.Client
 	Scheme        *runtime.Scheme
 	EventRecorder *EventRecorder
 	VaultConfig   vault.AppConfig
-	VaultClient   *vaultAPI.Client
 	K8ClientSet   *kubernetes.Clientset
-	authSACache   map[string]*vault.AuthServiceAccount
-	saCacheMx     sync.RWMutex
+	authCache     *authCache
+	// requestLimiter limits Vault requests in flight of all reconciles
+	requestLimiter *vault.RequestLimiter
+	// apiReader reads objects, which aren't worth caching, directly from the API server
+	apiReader client.Reader
 }
 
-func NewVaultReconciler(mgr manager.Manager, cfg vault.AppConfig, vaultClient *vaultAPI.Client,
-	k8ClientSet *kubernetes.Clientset) (*VaultSecretReconciler, error) {
+func NewVaultReconciler(mgr manager.Manager, cfg vault.AppConfig, k8ClientSet *kubernetes.Clientset) (*VaultSecretReconciler, error) {
 	reconciler := &VaultSecretReconciler{
-		Client:        mgr.GetClient(),
-		Scheme:        mgr.GetScheme(),
-		EventRecorder: &EventRecorder{Recorder: mgr.GetEventRecorderFor("vault-operator")},
-		VaultConfig:   cfg,
-		VaultClient:   vaultClient,
-		K8ClientSet:   k8ClientSet,
-		authSACache:   make(map[string]*vault.AuthServiceAccount),
+		Client:         mgr.GetClient(),
+		Scheme:         mgr.GetScheme(),
+		EventRecorder:  &EventRecorder{Recorder: mgr.GetEventRecorderFor("vault-operator")},
+		VaultConfig:    cfg,
+		K8ClientSet:    k8ClientSet,
+		authCache:      newAuthCache(cfg.AuthCacheIdleTimeout),
+		requestLimiter: vault.NewRequestLimiter(cfg.MaxVaultRequests),
+		apiReader:      mgr.GetAPIReader(),
 	}
 	err := reconciler.setupWithManager(mgr)
 	if err != nil {
 		return nil, err
 	}
+	if err := mgr.Add(reconciler.authCache); err != nil {
+		return nil, fmt.Errorf("add auth cache: %w", err)
+	}
 	return reconciler, nil
 }
 
 //+kubebuilder:rbac:groups=k8s.kiwi.com,resources=vaultsecrets,verbs=get;list;watch;create;update;patch;delete
 //+kubebuilder:rbac:groups=k8s.kiwi.com,resources=vaultsecrets/status,verbs=get;update;patch
 //+kubebuilder:rbac:groups=k8s.kiwi.com,resources=vaultsecrets/finalizers,verbs=update
+//+kubebuilder:rbac:groups="",resources=configmaps,verbs=get;list;watch;create;update;patch
 
 // Reconcile is part of the main kubernetes reconciliation loop which aims to
 // move the current state of the cluster closer to the desired state.
@@ -112,8 +128,26 @@ func (r *VaultSecretReconciler) Reconcile(ctx context.Context, req ctrl.Request)
 		return ctrl.Result{}, err
 	}
 
-	if err := r.validateResource(&vaultSecret, req); err != nil {
+	if !vaultSecret.DeletionTimestamp.IsZero() {
+		logger.Info("VaultSecret is being deleted")
+		return ctrl.Result{}, r.finalize(ctx, &vaultSecret)
+	}
+	if err := r.ensureFinalizer(ctx, &vaultSecret); err != nil {
+		return ctrl.Result{}, err
+	}
+
+	if vaultSecret.Annotations[k8skiwicomv1.PausedAnnotation] == "true" {
+		logger.Info("VaultSecret is paused, skipping sync")
+		setCondition(&vaultSecret, k8skiwicomv1.ConditionPaused, metav1.ConditionTrue, reasonPaused,
+			"Syncing is paused by the "+k8skiwicomv1.PausedAnnotation+" annotation")
+		// the annotation change un-pauses the resource, so no re-queuing
+		return ctrl.Result{}, updateVaultSecretStatus(ctx, r.Client, &vaultSecret)
+	}
+	meta.RemoveStatusCondition(&vaultSecret.Status.Conditions, k8skiwicomv1.ConditionPaused)
+
+	if err := r.validateResource(&vaultSecret); err != nil {
 		r.EventRecorder.Warning(&vaultSecret, "invalid resource", err)
+		r.syncFailed(ctx, &vaultSecret, reasonInvalidResource, err)
 		// since the resource is invalid, and it can't be magically fixed, someone has to manually fix it
 		// so no re-queuing
 		logger.Error(err, "validate")
@@ -133,24 +167,55 @@ func (r *VaultSecretReconciler) Reconcile(ctx context.Context, req ctrl.Request)
 		operatorMetrics.ReconcileDuration.With(labels).Observe(time.Since(startedAt).Seconds())
 	}()
 
-	var tokener vault.Tokener
 	if vaultSecret.Spec.Auth.Token != "" {
-		tokener = vault.NewAuthToken(vaultSecret.Spec.Auth.Token)
-	} else {
-		saAccount, err := r.getAuthServiceAccount(vaultSecret)
-		if err != nil {
-			return ctrl.Result{}, fmt.Errorf("get auth service account: %w", err)
-		}
-		tokener = saAccount
+		r.EventRecorder.Warning(&vaultSecret, "deprecated", k8skiwicomv1.ErrInlineTokenDeprecated)
 	}
-	reader, err := vault.NewReader(tokener, &vaultSecret, logger, &r.VaultConfig)
+
+	caCert, err := r.getCACert(ctx, vaultSecret)
+	if err != nil {
+		r.EventRecorder.Warning(&vaultSecret, "tls failed", err)
+		r.syncFailed(ctx, &vaultSecret, reasonTLSFailed, err)
+		return ctrl.Result{}, err
+	}
+
+	tokener, err := r.getTokener(ctx, vaultSecret, caCert)
+	if err != nil {
+		r.EventRecorder.Warning(&vaultSecret, "auth failed", err)
+		r.syncFailed(ctx, &vaultSecret, reasonAuthFailed, err)
+		return ctrl.Result{}, err
+	}
+	reader, err := vault.NewReader(tokener, &vaultSecret, logger, &r.VaultConfig, vault.WithCACert(caCert),
+		vault.WithRequestLimiter(r.requestLimiter))
 	if err != nil {
 		r.EventRecorder.Warning(&vaultSecret, "vault failed", err)
+		if errors.Is(err, vault.ErrAuth) {
+			r.syncFailed(ctx, &vaultSecret, reasonAuthFailed, err)
+		} else {
+			r.syncFailed(ctx, &vaultSecret, reasonVaultFailed, err)
+		}
 		return ctrl.Result{}, err
 	}
 
 	if err := reader.ReadData(ctx); err != nil {
+		if errors.Is(err, vault.ErrMissingPaths) {
+			// the existing target is left untouched, so it isn't synced with missing keys
+			r.EventRecorder.Warning(&vaultSecret, "paths missing", err)
+			r.syncFailed(ctx, &vaultSecret, reasonPathsMissing, err)
+			return ctrl.Result{}, err
+		}
+		if errors.Is(err, vault.ErrPathLimit) {
+			// listing the same paths again would exceed the limits again, so retry only after the reconcile period
+			r.EventRecorder.Warning(&vaultSecret, "path limit exceeded", err)
+			r.syncFailed(ctx, &vaultSecret, reasonPathLimitExceeded, err)
+			return ctrl.Result{RequeueAfter: reconcileAfter}, nil
+		}
+		if errors.Is(err, vault.ErrOverride) {
+			r.EventRecorder.Warning(&vaultSecret, "sync rejected", err)
+			r.syncFailed(ctx, &vaultSecret, reasonSyncRejected, err)
+			return ctrl.Result{}, err
+		}
 		r.EventRecorder.Warning(&vaultSecret, "vault read failed", err)
+		r.syncFailed(ctx, &vaultSecret, reasonVaultReadFailed, err)
 		return ctrl.Result{}, err
 	}
 
@@ -167,168 +232,467 @@ func (r *VaultSecretReconciler) Reconcile(ctx context.Context, req ctrl.Request)
 	if err != nil {
 		logger.Info(fmt.Sprintf("VaultOperator sync rejected: %v", err))
 		r.EventRecorder.Warning(&vaultSecret, "sync rejected", err)
+		r.syncFailed(ctx, &vaultSecret, reasonSyncRejected, err)
 		return ctrl.Result{}, nil
 	}
 
-	// Set VaultSecret as the owner and controller
-	if err := controllerutil.SetControllerReference(&vaultSecret, k8sSecret, r.Scheme); err != nil {
-		return ctrl.Result{}, err
+	kind := vaultSecret.Spec.GetTargetKind()
+	var target client.Object = k8sSecret
+	if kind == k8skiwicomv1.TargetKindConfigMap {
+		target = vault.NewConfigMap(k8sSecret)
+	}
+
+	// the watch of targets skips updates, which keep the data stamped by the operator
+	target.GetAnnotations()[contentHashAnnotation] = vault.ContentHash(vault.ObjectData(target))
+
+	// Set VaultSecret as the owner and controller. Targets kept by the deletion policy have no owner reference,
+	// since the garbage collector deletes them under foreground deletion before the finalizer releases them.
+	if !needsFinalizer(&vaultSecret) {
+		if err := controllerutil.SetControllerReference(&vaultSecret, target, r.Scheme); err != nil {
+			return ctrl.Result{}, err
+		}
 	}
 
-	// Check if this Secret already exists
-	var found corev1.Secret
-	err = r.Client.Get(ctx, types.NamespacedName{Name: k8sSecret.Name, Namespace: k8sSecret.Namespace}, &found)
+	// Check if the target already exists
+	created := false
+	found := vault.NewTargetObject(kind)
+	err = r.Client.Get(ctx, client.ObjectKeyFromObject(target), found)
 	if err != nil {
 		if !k8Errors.IsNotFound(err) {
 			return ctrl.Result{}, err
 		}
-		logger.Info("Creating a new Secret Secret.namespace: " + k8sSecret.Namespace + " Secret.name: " + k8sSecret.Name)
-		err = r.Client.Create(ctx, k8sSecret)
+		logger.Info("Creating a new " + kind + " " + kind + ".namespace: " + target.GetNamespace() + " " + kind + ".name: " + target.GetName())
+		err = r.Client.Create(ctx, target)
 		if err != nil {
+			r.syncFailed(ctx, &vaultSecret, reasonSecretWriteFailed, err)
 			return ctrl.Result{}, err
 		}
-		r.EventRecorder.Normal(&vaultSecret, "created", "Secret has been created.")
+		created = true
+		if driftDetected(&vaultSecret, nil) {
+			r.driftCorrected(&vaultSecret, kind+" has been deleted outside of the operator, it was re-created.")
+		} else {
+			r.EventRecorder.Normal(&vaultSecret, "created", kind+" has been created.")
+		}
+	} else if secret, ok := found.(*corev1.Secret); ok && secret.Type != k8sSecret.Type {
+		logger.Info("Secret type has changed, re-creating the Secret", "from", secret.Type, "to", k8sSecret.Type)
+		if err := r.recreateSecret(ctx, secret, k8sSecret); err != nil {
+			r.syncFailed(ctx, &vaultSecret, reasonSecretWriteFailed, err)
+			return ctrl.Result{}, err
+		}
+		created = true
+		r.EventRecorder.Normal(&vaultSecret, "recreated", fmt.Sprintf("Secret has been re-created, because its type has changed to %q.", k8sSecret.Type))
 	} else {
-		managedBy := found.Labels["managed-by"]
+		managedBy := found.GetLabels()["managed-by"]
 		if managedBy != "" && managedBy != vault.ManagedByLabel {
-			logger.Info("syncing existing secret, that was not managed by vault operator", "name", found.Name)
+			logger.Info("syncing existing "+strings.ToLower(kind)+", that was not managed by vault operator", "name", found.GetName())
 		}
 	}
 
-	// Secret already exists - preserve existing annotations we don't manage
-	if found.Annotations != nil {
-		for key, value := range found.Annotations {
-			if _, exists := k8sSecret.Annotations[key]; !exists {
-				k8sSecret.Annotations[key] = value
-			}
+	// Target already exists - preserve existing annotations we don't manage
+	annotations := target.GetAnnotations()
+	for key, value := range found.GetAnnotations() {
+		if _, exists := annotations[key]; !exists {
+			annotations[key] = value
 		}
 	}
 
 	// Check if update is needed
-	eq := reflect.DeepEqual(k8sSecret.Data, found.Data) && reflect.DeepEqual(k8sSecret.Annotations, found.Annotations)
-	if !eq {
-		logger.Info("Secret exists, updating: " + k8sSecret.Namespace + " Secret.name: " + k8sSecret.Name)
+	dataChanged := created || !reflect.DeepEqual(vault.ObjectData(target), vault.ObjectData(found))
+	eq := !dataChanged && reflect.DeepEqual(annotations, found.GetAnnotations()) &&
+		reflect.DeepEqual(target.GetOwnerReferences(), found.GetOwnerReferences())
+	if !created && !eq {
+		logger.Info(kind + " exists, updating: " + target.GetNamespace() + " " + kind + ".name: " + target.GetName())
 
-		err = r.Client.Update(ctx, k8sSecret)
+		err = r.Client.Update(ctx, target)
 		if err != nil {
+			r.syncFailed(ctx, &vaultSecret, reasonSecretWriteFailed, err)
 			return ctrl.Result{}, err
 		}
 
-		r.EventRecorder.Normal(&vaultSecret, "updated", "Secret has been updated.")
+		if driftDetected(&vaultSecret, found) {
+			r.driftCorrected(&vaultSecret, kind+" has been modified outside of the operator, it was restored.")
+		} else {
+			r.EventRecorder.Normal(&vaultSecret, "updated", kind+" has been updated.")
+		}
 	}
-	if err := updateVaultSecretResource(ctx, r.Client, &vaultSecret); err != nil {
+	setSyncSucceeded(&vaultSecret, target, reader.GetPathStatuses())
+	rolloutErr := r.rolloutRestart(ctx, &vaultSecret, vaultSecret.Status.ContentHash)
+	if err := updateVaultSecretStatus(ctx, r.Client, &vaultSecret); err != nil {
 		return ctrl.Result{}, err
 	}
+	if rolloutErr != nil {
+		// the target is in sync, but workloads may still use the stale data, so the restart is retried
+		return ctrl.Result{}, rolloutErr
+	}
 
 	return ctrl.Result{RequeueAfter: reconcileAfter}, nil
 }
 
-func (r *VaultSecretReconciler) cachedSA(id string) *vault.AuthServiceAccount {
-	r.saCacheMx.RLock()
-	defer r.saCacheMx.RUnlock()
-	return r.authSACache[id]
+// recreateSecret replaces found with secret, since the type of a Secret is immutable.
+// Annotations of found, which aren't managed by the operator, are kept.
+func (r *VaultSecretReconciler) recreateSecret(ctx context.Context, found, secret *corev1.Secret) error {
+	for key, value := range found.Annotations {
+		if _, exists := secret.Annotations[key]; !exists {
+			secret.Annotations[key] = value
+		}
+	}
+
+	uid := found.UID
+	if err := r.Client.Delete(ctx, found, client.Preconditions{UID: &uid}); err != nil && !k8Errors.IsNotFound(err) {
+		return fmt.Errorf("delete secret: %w", err)
+	}
+	if err := r.Client.Create(ctx, secret); err != nil {
+		return fmt.Errorf("create secret: %w", err)
+	}
+	return nil
 }
 
-func (r *VaultSecretReconciler) getAuthServiceAccount(vaultSecret k8skiwicomv1.VaultSecret) (*vault.AuthServiceAccount, error) {
-	saRef := vaultSecret.Spec.Auth.ServiceAccountRef
-	id := fmt.Sprintf("%s-%s-%s-%s-%s", vaultSecret.Spec.Addr, vaultSecret.Namespace, saRef.Name, saRef.Role, saRef.AuthPath)
-	saAccount := r.cachedSA(id)
-	if saAccount != nil {
+func (r *VaultSecretReconciler) getTokener(ctx context.Context, vaultSecret k8skiwicomv1.VaultSecret, caCert []byte) (vault.Tokener, error) {
+	switch {
+	case vaultSecret.Spec.Auth.Token != "":
+		return vault.NewAuthToken(vaultSecret.Spec.Auth.Token), nil
+	case vaultSecret.Spec.Auth.TokenSecretRef != nil:
+		token, err := r.getSecretToken(ctx, vaultSecret)
+		if err != nil {
+			return nil, fmt.Errorf("get token from secret: %w", err)
+		}
+		return vault.NewAuthToken(token), nil
+	case vaultSecret.Spec.Auth.AppRole != nil:
+		appRole, err := r.getAuthAppRole(vaultSecret, caCert)
+		if err != nil {
+			return nil, fmt.Errorf("get auth app role: %w", err)
+		}
+		return appRole, nil
+	case vaultSecret.Spec.Auth.Cert != nil:
+		cert, err := r.getAuthCert(vaultSecret, caCert)
+		if err != nil {
+			return nil, fmt.Errorf("get auth cert: %w", err)
+		}
+		return cert, nil
+	case vaultSecret.Spec.Auth.JWT != nil:
+		jwt, err := r.getAuthJWT(vaultSecret, caCert)
+		if err != nil {
+			return nil, fmt.Errorf("get auth jwt: %w", err)
+		}
+		return jwt, nil
+	default:
+		saAccount, err := r.getAuthServiceAccount(vaultSecret, caCert)
+		if err != nil {
+			return nil, fmt.Errorf("get auth service account: %w", err)
+		}
 		return saAccount, nil
 	}
+}
+
+// getCACert reads the CA bundle referenced by spec.tls.caSecretRef.
+func (r *VaultSecretReconciler) getCACert(ctx context.Context, vaultSecret k8skiwicomv1.VaultSecret) ([]byte, error) {
+	if vaultSecret.Spec.TLS == nil || vaultSecret.Spec.TLS.CASecretRef == nil {
+		return nil, nil
+	}
+
+	ref := vaultSecret.Spec.TLS.CASecretRef
+	var secret corev1.Secret
+	if err := r.Client.Get(ctx, types.NamespacedName{Namespace: vaultSecret.Namespace, Name: ref.Name}, &secret); err != nil {
+		return nil, fmt.Errorf("get CA secret: %w", err)
+	}
+
+	caCert := secret.Data[ref.Key]
+	if len(caCert) == 0 {
+		return nil, fmt.Errorf("key %q not found in secret %q", ref.Key, ref.Name)
+	}
+
+	return caCert, nil
+}
+
+// getSecretToken reads the Vault token referenced by spec.auth.tokenSecretRef.
+func (r *VaultSecretReconciler) getSecretToken(ctx context.Context, vaultSecret k8skiwicomv1.VaultSecret) (string, error) {
+	ref := vaultSecret.Spec.Auth.TokenSecretRef
+	var secret corev1.Secret
+	if err := r.Client.Get(ctx, types.NamespacedName{Namespace: vaultSecret.Namespace, Name: ref.Name}, &secret); err != nil {
+		return "", err
+	}
 
-	vaultClient, err := r.VaultClient.Clone()
+	token := strings.TrimSpace(string(secret.Data[ref.Key]))
+	if token == "" {
+		return "", fmt.Errorf("key %q not found in secret %q", ref.Key, ref.Name)
+	}
+
+	return token, nil
+}
+
+func (r *VaultSecretReconciler) loginClient(vaultSecret k8skiwicomv1.VaultSecret, caCert []byte) (*vaultAPI.Client, error) {
+	vaultClient, err := vault.NewClientForAddr(r.VaultConfig, vaultSecret.Spec.Addr, caCert)
 	if err != nil {
-		return nil, fmt.Errorf("vault client clone: %w", err)
+		return nil, fmt.Errorf("vault client: %w", err)
 	}
-	if err := vaultClient.SetAddress(vaultSecret.Spec.Addr); err != nil {
-		return nil, fmt.Errorf("vault set address: %w", err)
+	if vaultSecret.Spec.Namespace != "" {
+		vaultClient.SetNamespace(vaultSecret.Spec.Namespace)
+	} else {
+		vaultClient.ClearNamespace()
 	}
-	saAccount = vault.NewAuthServiceAccount(vaultClient, r.K8ClientSet, saRef.Name, vaultSecret.Namespace, saRef.Role,
-		saRef.AuthPath, false, r.VaultConfig.RefreshTokenBefore)
-	r.saCacheMx.Lock()
-	defer r.saCacheMx.Unlock()
-	r.authSACache[id] = saAccount
-	return saAccount, nil
+	// login doesn't need a token, make sure VAULT_TOKEN from environment isn't used
+	vaultClient.ClearToken()
+	return vaultClient, nil
+}
+
+// authCacheID returns an auth cache key, which is unique for the Vault connection settings and the given auth parts.
+func authCacheID(vaultSecret k8skiwicomv1.VaultSecret, caCert []byte, parts ...string) string {
+	caHash := sha256.Sum256(caCert)
+	return fmt.Sprintf("%s-%s-%x-%s-%s", vaultSecret.Spec.Addr, vaultSecret.Spec.Namespace, caHash, vaultSecret.Namespace,
+		strings.Join(parts, "-"))
+}
+
+func (r *VaultSecretReconciler) getAuthServiceAccount(vaultSecret k8skiwicomv1.VaultSecret, caCert []byte) (vault.Tokener, error) {
+	saRef := vaultSecret.Spec.Auth.ServiceAccountRef
+	id := authCacheID(vaultSecret, caCert, "sa", saRef.Name, saRef.Role, saRef.AuthPath,
+		strings.Join(saRef.Audiences, ","), expirationSecondsID(saRef.ExpirationSeconds))
+	return r.authCache.getOrSet(id, referencedSecretNames(&vaultSecret), func() (vault.Tokener, error) {
+		vaultClient, err := r.loginClient(vaultSecret, caCert)
+		if err != nil {
+			return nil, err
+		}
+		return vault.NewAuthServiceAccount(vaultClient, r.K8ClientSet, saRef.Name, vaultSecret.Namespace, saRef.Role,
+			saRef.AuthPath, false, r.VaultConfig.RefreshTokenBefore).
+			WithTokenRequest(saRef.Audiences, saRef.ExpirationSeconds), nil
+	})
+}
+
+func (r *VaultSecretReconciler) getAuthJWT(vaultSecret k8skiwicomv1.VaultSecret, caCert []byte) (vault.Tokener, error) {
+	jwt := vaultSecret.Spec.Auth.JWT
+	id := authCacheID(vaultSecret, caCert, "jwt", jwt.ServiceAccountName, jwt.Role, jwt.AuthPath,
+		strings.Join(jwt.Audiences, ","), expirationSecondsID(jwt.ExpirationSeconds))
+	return r.authCache.getOrSet(id, referencedSecretNames(&vaultSecret), func() (vault.Tokener, error) {
+		vaultClient, err := r.loginClient(vaultSecret, caCert)
+		if err != nil {
+			return nil, err
+		}
+		return vault.NewAuthJWT(vaultClient, r.K8ClientSet, jwt.ServiceAccountName, vaultSecret.Namespace, jwt.Role,
+			jwt.AuthPath, r.VaultConfig.RefreshTokenBefore).
+			WithTokenRequest(jwt.Audiences, jwt.ExpirationSeconds), nil
+	})
+}
+
+func expirationSecondsID(expirationSeconds *int64) string {
+	if expirationSeconds == nil {
+		return ""
+	}
+	return strconv.FormatInt(*expirationSeconds, 10)
+}
+
+func (r *VaultSecretReconciler) getAuthAppRole(vaultSecret k8skiwicomv1.VaultSecret, caCert []byte) (vault.Tokener, error) {
+	appRole := vaultSecret.Spec.Auth.AppRole
+	id := authCacheID(vaultSecret, caCert, "approle", appRole.SecretName, appRole.RoleIDKey, appRole.SecretIDKey, appRole.AuthPath)
+	return r.authCache.getOrSet(id, referencedSecretNames(&vaultSecret), func() (vault.Tokener, error) {
+		vaultClient, err := r.loginClient(vaultSecret, caCert)
+		if err != nil {
+			return nil, err
+		}
+		return vault.NewAuthAppRole(vaultClient, r.K8ClientSet, appRole.SecretName, vaultSecret.Namespace,
+			appRole.RoleIDKey, appRole.SecretIDKey, appRole.AuthPath, r.VaultConfig.RefreshTokenBefore), nil
+	})
+}
+
+func (r *VaultSecretReconciler) getAuthCert(vaultSecret k8skiwicomv1.VaultSecret, caCert []byte) (vault.Tokener, error) {
+	cert := vaultSecret.Spec.Auth.Cert
+	id := authCacheID(vaultSecret, caCert, "cert", cert.SecretName, cert.Name, cert.AuthPath)
+	return r.authCache.getOrSet(id, referencedSecretNames(&vaultSecret), func() (vault.Tokener, error) {
+		vaultClient, err := r.loginClient(vaultSecret, caCert)
+		if err != nil {
+			return nil, err
+		}
+		return vault.NewAuthCert(vaultClient, r.K8ClientSet, cert.SecretName, vaultSecret.Namespace, cert.Name,
+			cert.AuthPath, r.VaultConfig.RefreshTokenBefore), nil
+	})
 }
 
 // setupWithManager sets up the controller with the Manager.
 func (r *VaultSecretReconciler) setupWithManager(mgr ctrl.Manager) error {
+	err := mgr.GetFieldIndexer().IndexField(context.Background(), &k8skiwicomv1.VaultSecret{}, referencedSecretsIndex,
+		func(obj client.Object) []string {
+			return referencedSecrets(obj.(*k8skiwicomv1.VaultSecret))
+		})
+	if err != nil {
+		return fmt.Errorf("index referenced secrets: %w", err)
+	}
+	err = mgr.GetFieldIndexer().IndexField(context.Background(), &k8skiwicomv1.VaultSecret{}, targetIndex,
+		func(obj client.Object) []string {
+			vaultSecret := obj.(*k8skiwicomv1.VaultSecret)
+			return []string{targetIndexKey(vaultSecret.Spec.GetTargetKind(), targetName(vaultSecret))}
+		})
+	if err != nil {
+		return fmt.Errorf("index targets: %w", err)
+	}
+
 	return ctrl.NewControllerManagedBy(mgr).
-		For(&k8skiwicomv1.VaultSecret{}).
-		WithEventFilter(predicate.GenerationChangedPredicate{}).
+		For(&k8skiwicomv1.VaultSecret{}, builder.WithPredicates(predicate.Or(
+			predicate.GenerationChangedPredicate{},
+			annotationChangedPredicate(k8skiwicomv1.ForceSyncAnnotation, k8skiwicomv1.PausedAnnotation),
+		))).
+		// targets are matched by name, since those kept by the deletion policy have no owner reference
+		Watches(&corev1.Secret{}, handler.EnqueueRequestsFromMapFunc(r.vaultSecretsSyncingInto(k8skiwicomv1.TargetKindSecret)),
+			builder.WithPredicates(managedSecretPredicate())).
+		Watches(&corev1.ConfigMap{}, handler.EnqueueRequestsFromMapFunc(r.vaultSecretsSyncingInto(k8skiwicomv1.TargetKindConfigMap)),
+			builder.WithPredicates(managedSecretPredicate())).
+		Watches(&corev1.Secret{}, handler.EnqueueRequestsFromMapFunc(r.vaultSecretsReferencingSecret),
+			builder.WithPredicates(predicate.ResourceVersionChangedPredicate{})).
 		WithOptions(controller.Options{
 			MaxConcurrentReconciles: r.VaultConfig.MaxConcurrentReconciles,
 		}).
 		Complete(r)
 }
 
-func (r *VaultSecretReconciler) validateResource(vaultSecret *k8skiwicomv1.VaultSecret, req reconcile.Request) error {
-	if vaultSecret.Spec.TargetSecretName == "" {
-		vaultSecret.Spec.TargetSecretName = vaultSecret.Name
+// annotationChangedPredicate passes updates, which change a value of any of the annotations.
+func annotationChangedPredicate(annotations ...string) predicate.Predicate {
+	return predicate.Funcs{
+		UpdateFunc: func(e event.UpdateEvent) bool {
+			if e.ObjectOld == nil || e.ObjectNew == nil {
+				return false
+			}
+			for _, annotation := range annotations {
+				if e.ObjectOld.GetAnnotations()[annotation] != e.ObjectNew.GetAnnotations()[annotation] {
+					return true
+				}
+			}
+			return false
+		},
 	}
+}
 
-	if vaultSecret.Spec.TargetFormat == "" {
-		vaultSecret.Spec.TargetFormat = vault.TypeEnv
+// managedSecretPredicate passes modifications and deletions of Secrets and ConfigMaps managed by the operator,
+// creations are skipped, since the operator creates them itself. Updates are skipped, when the data matches
+// the hash stamped by the operator, so writes of the operator don't trigger another sync.
+func managedSecretPredicate() predicate.Predicate {
+	isManaged := func(obj client.Object) bool {
+		return obj.GetLabels()["managed-by"] == vault.ManagedByLabel
 	}
-
-	if vaultSecret.Spec.ReconcilePeriod == "" {
-		vaultSecret.Spec.ReconcilePeriod = r.VaultConfig.DefaultReconcilePeriod
+	return predicate.Funcs{
+		CreateFunc: func(event.CreateEvent) bool { return false },
+		UpdateFunc: func(e event.UpdateEvent) bool {
+			if !isManaged(e.ObjectOld) && !isManaged(e.ObjectNew) {
+				return false
+			}
+			return e.ObjectNew.GetAnnotations()[contentHashAnnotation] != vault.ContentHash(vault.ObjectData(e.ObjectNew))
+		},
+		DeleteFunc:  func(e event.DeleteEvent) bool { return isManaged(e.Object) },
+		GenericFunc: func(event.GenericEvent) bool { return false },
 	}
+}
 
-	_, err := time.ParseDuration(vaultSecret.Spec.ReconcilePeriod)
-	if err != nil {
-		return fmt.Errorf("VaultSecret.Spec.ReconcilePeriod is invalid: %w", err)
+// driftDetected reports whether the target Secret was modified or deleted outside of the operator
+// since the last sync, found is nil when the Secret doesn't exist.
+func driftDetected(vaultSecret *k8skiwicomv1.VaultSecret, found client.Object) bool {
+	// nothing was synced yet or the spec has changed since the last sync
+	if vaultSecret.Status.ContentHash == "" || vaultSecret.Status.ObservedGeneration != vaultSecret.Generation {
+		return false
 	}
+	return found == nil || vault.ContentHash(vault.ObjectData(found)) != vaultSecret.Status.ContentHash
+}
 
-	if vaultSecret.Spec.Addr == "" {
-		if r.VaultConfig.DefaultVaultAddr == "" {
-			return errors.New("default vault addr from app config is empty")
-		}
+func (r *VaultSecretReconciler) driftCorrected(vaultSecret *k8skiwicomv1.VaultSecret, msg string) {
+	r.EventRecorder.Normal(vaultSecret, "drift corrected", msg)
+	operatorMetrics.DriftCorrectedCount.With(map[string]string{
+		"namespace": vaultSecret.Namespace,
+		"name":      vaultSecret.Name,
+	}).Inc()
+}
 
-		vaultSecret.Spec.Addr = r.VaultConfig.DefaultVaultAddr
+// referencedSecretNames returns namespaced names of Secrets the VaultSecret reads its configuration from.
+func referencedSecretNames(vaultSecret *k8skiwicomv1.VaultSecret) []types.NamespacedName {
+	var names []types.NamespacedName
+	for _, name := range referencedSecrets(vaultSecret) {
+		names = append(names, types.NamespacedName{Namespace: vaultSecret.Namespace, Name: name})
 	}
+	return names
+}
 
-	if vaultSecret.Spec.Auth.Token != "" {
+// referencedSecrets returns names of Secrets the VaultSecret reads its configuration from.
+func referencedSecrets(vaultSecret *k8skiwicomv1.VaultSecret) []string {
+	var names []string
+	if ref := vaultSecret.Spec.Auth.TokenSecretRef; ref != nil && ref.Name != "" {
+		names = append(names, ref.Name)
+	}
+	if appRole := vaultSecret.Spec.Auth.AppRole; appRole != nil && appRole.SecretName != "" {
+		names = append(names, appRole.SecretName)
+	}
+	if cert := vaultSecret.Spec.Auth.Cert; cert != nil && cert.SecretName != "" {
+		names = append(names, cert.SecretName)
+	}
+	if tls := vaultSecret.Spec.TLS; tls != nil && tls.CASecretRef != nil && tls.CASecretRef.Name != "" {
+		names = append(names, tls.CASecretRef.Name)
+	}
+	return names
+}
+
+// vaultSecretsReferencingSecret enqueues VaultSecrets that reference the changed Secret, so rotated credentials
+// are used right away. Cached logins configured from the Secret are evicted and revoked, the enqueued
+// reconciles log in again with the new credentials.
+func (r *VaultSecretReconciler) vaultSecretsReferencingSecret(ctx context.Context, obj client.Object) []reconcile.Request {
+	revokeTokens(ctx, ctrl.LoggerFrom(ctx), r.authCache.evictSecret(client.ObjectKeyFromObject(obj)))
+
+	var list k8skiwicomv1.VaultSecretList
+	err := r.Client.List(ctx, &list, client.InNamespace(obj.GetNamespace()),
+		client.MatchingFields{referencedSecretsIndex: obj.GetName()})
+	if err != nil {
+		ctrl.LoggerFrom(ctx).Error(err, "list VaultSecrets referencing secret", "secret", obj.GetName())
 		return nil
 	}
 
-	if vaultSecret.Spec.Auth.ServiceAccountRef == nil {
-		vaultSecret.Spec.Auth.ServiceAccountRef = &k8skiwicomv1.VaultSecretAuthServiceAccountRefSpec{}
+	requests := make([]reconcile.Request, 0, len(list.Items))
+	for _, item := range list.Items {
+		requests = append(requests, reconcile.Request{
+			NamespacedName: types.NamespacedName{Namespace: item.Namespace, Name: item.Name},
+		})
 	}
+	return requests
+}
 
-	if vaultSecret.Spec.Auth.ServiceAccountRef.Name == "" {
-		if r.VaultConfig.DefaultSAName == "" {
-			return errors.New("default SA name from app config is empty")
-		}
-		vaultSecret.Spec.Auth.ServiceAccountRef.Name = r.VaultConfig.DefaultSAName
+func targetIndexKey(kind, name string) string {
+	return kind + "/" + name
+}
+
+// targetName returns the name of the target Secret or ConfigMap of the VaultSecret.
+func targetName(vaultSecret *k8skiwicomv1.VaultSecret) string {
+	if vaultSecret.Spec.TargetSecretName != "" {
+		return vaultSecret.Spec.TargetSecretName
 	}
+	return vaultSecret.Name
+}
 
-	if vaultSecret.Spec.Auth.ServiceAccountRef.AuthPath == "" {
-		if r.VaultConfig.DefaultSAAuthPath == "" {
-			return errors.New("default SA auth path from app config is empty")
+// vaultSecretsSyncingInto returns a handler.MapFunc, which enqueues VaultSecrets syncing into
+// the changed target of the kind, so modifications outside of the operator are corrected right away.
+func (r *VaultSecretReconciler) vaultSecretsSyncingInto(kind string) handler.MapFunc {
+	return func(ctx context.Context, obj client.Object) []reconcile.Request {
+		var list k8skiwicomv1.VaultSecretList
+		err := r.Client.List(ctx, &list, client.InNamespace(obj.GetNamespace()),
+			client.MatchingFields{targetIndex: targetIndexKey(kind, obj.GetName())})
+		if err != nil {
+			ctrl.LoggerFrom(ctx).Error(err, "list VaultSecrets syncing into target", "kind", kind, "target", obj.GetName())
+			return nil
 		}
 
-		vaultSecret.Spec.Auth.ServiceAccountRef.AuthPath = r.VaultConfig.DefaultSAAuthPath
+		requests := make([]reconcile.Request, 0, len(list.Items))
+		for _, item := range list.Items {
+			requests = append(requests, reconcile.Request{
+				NamespacedName: types.NamespacedName{Namespace: item.Namespace, Name: item.Name},
+			})
+		}
+		return requests
 	}
+}
 
-	if vaultSecret.Spec.Auth.ServiceAccountRef.Role == "" {
-		if r.VaultConfig.Role != "" {
-			vaultSecret.Spec.Auth.ServiceAccountRef.Role = r.VaultConfig.Role
-		} else {
-			vaultSecret.Spec.Auth.ServiceAccountRef.Role = req.Namespace
-		}
+func (r *VaultSecretReconciler) validateResource(vaultSecret *k8skiwicomv1.VaultSecret) error {
+	if err := vaultSecret.Spec.Validate(); err != nil {
+		return err
 	}
 
-	return nil
-}
+	if err := vaultSecret.Default(r.VaultConfig.VaultSecretDefaults()); err != nil {
+		return err
+	}
 
-func updateVaultSecretResource(ctx context.Context, c client.Client, secret *k8skiwicomv1.VaultSecret) error {
-	// only status is updated here
-	secret.Status.LastUpdated = metav1.Now().Format(time.RFC3339)
-	err := c.Status().Update(ctx, secret)
+	// the default reconcile period from app config isn't checked by Validate
+	_, err := time.ParseDuration(vaultSecret.Spec.ReconcilePeriod)
 	if err != nil {
-		return fmt.Errorf("failed to update resource status: %w", err)
+		return fmt.Errorf("VaultSecret.Spec.ReconcilePeriod is invalid: %w", err)
 	}
 
 	return nil
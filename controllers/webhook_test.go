@@ -0,0 +1,135 @@
+package controllers
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	admissionregistrationv1 "k8s.io/api/admissionregistration/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	k8skiwicomv1 "github.com/kiwicom/k8s-vault-operator/api/v1"
+)
+
+func newWebhookVaultSecret(name string) *k8skiwicomv1.VaultSecret {
+	return &k8skiwicomv1.VaultSecret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: namespace,
+		},
+		Spec: k8skiwicomv1.VaultSecretSpec{
+			Addr: "http://127.0.0.1:8200",
+			Auth: k8skiwicomv1.VaultSecretAuthSpec{
+				Token: "testtoken",
+			},
+			Paths: []k8skiwicomv1.VaultSecretPath{
+				{Path: "secret/seeds/team1/project1/secret"},
+			},
+		},
+	}
+}
+
+var _ = Describe("Validating webhook", func() {
+	DescribeTable("should reject invalid VaultSecrets",
+		func(mutate func(*k8skiwicomv1.VaultSecret), message string) {
+			vs := newWebhookVaultSecret("test-webhook-invalid")
+			mutate(vs)
+			err := k8sClient.Create(ctx, vs)
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring(message))
+		},
+		Entry("invalid reconcilePeriod", func(vs *k8skiwicomv1.VaultSecret) {
+			vs.Spec.ReconcilePeriod = "10 minutes"
+		}, "VaultSecret.Spec.ReconcilePeriod is invalid"),
+		Entry("double wildcard inside a path segment", func(vs *k8skiwicomv1.VaultSecret) {
+			vs.Spec.Paths = []k8skiwicomv1.VaultSecretPath{{Path: "secret/team**/secret"}}
+		}, `"**" has to be a whole path segment`),
+		Entry("pattern in the secrets engine mount", func(vs *k8skiwicomv1.VaultSecret) {
+			vs.Spec.Paths = []k8skiwicomv1.VaultSecretPath{{Path: "secret*/team1/secret"}}
+		}, "the secrets engine mount can't be a pattern"),
+		Entry("unknown targetFormat", func(vs *k8skiwicomv1.VaultSecret) {
+			vs.Spec.TargetFormat = "toml"
+		}, `VaultSecret.Spec.TargetFormat "toml" is invalid`),
+		Entry("invalid include pattern", func(vs *k8skiwicomv1.VaultSecret) {
+			vs.Spec.Paths[0].Include = []string{"[a"}
+		}, `pattern "[a" is invalid`),
+		Entry("keys renamed to the same key", func(vs *k8skiwicomv1.VaultSecret) {
+			vs.Spec.Paths[0].KeyMap = map[string]string{"a": "c", "b": "c"}
+		}, `keys "a" and "b" are both mapped to "c"`),
+		Entry("token and serviceAccountRef", func(vs *k8skiwicomv1.VaultSecret) {
+			vs.Spec.Auth.ServiceAccountRef = &k8skiwicomv1.VaultSecretAuthServiceAccountRefSpec{Name: "default"}
+		}, "VaultSecret.Spec.Auth.Token and VaultSecret.Spec.Auth.ServiceAccountRef are mutually exclusive"),
+	)
+
+	It("should reject an invalid update", func() {
+		vs := newWebhookVaultSecret("test-webhook-update")
+		Expect(k8sClient.Create(ctx, vs)).To(Succeed())
+
+		vs.Spec.ReconcilePeriod = "often"
+		err := k8sClient.Update(ctx, vs)
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(ContainSubstring("VaultSecret.Spec.ReconcilePeriod is invalid"))
+	})
+})
+
+var _ = Describe("Defaulting webhook", func() {
+	It("should write the operator defaults into the spec", func() {
+		vs := newWebhookVaultSecret("test-webhook-defaults")
+		vs.Spec.Addr = ""
+		Expect(k8sClient.Create(ctx, vs)).To(Succeed())
+
+		Expect(vs.Spec.Addr).To(Equal("http://127.0.0.1:8200"))
+		Expect(vs.Spec.TargetSecretName).To(Equal(vs.Name))
+		Expect(vs.Spec.TargetFormat).To(Equal("env"))
+		Expect(vs.Spec.ReconcilePeriod).To(Equal("10m"))
+	})
+})
+
+// The defaulting webhook is disabled by default, so the reconciler applies the defaults itself.
+var _ = Describe("Defaulting without the webhook", Serial, func() {
+	BeforeEach(func() {
+		var webhookConfig admissionregistrationv1.MutatingWebhookConfiguration
+		Expect(k8sClient.Get(ctx, types.NamespacedName{Name: "mutating-webhook-configuration"}, &webhookConfig)).To(Succeed())
+		Expect(k8sClient.Delete(ctx, &webhookConfig)).To(Succeed())
+		DeferCleanup(func() {
+			webhookConfig.ResourceVersion = ""
+			webhookConfig.UID = ""
+			Expect(k8sClient.Create(ctx, &webhookConfig)).To(Succeed())
+		})
+
+		// the API server drops the webhook asynchronously
+		Eventually(func() string {
+			vs := newWebhookVaultSecret("test-defaults-dry-run")
+			vs.Spec.Addr = ""
+			Expect(k8sClient.Create(ctx, vs, client.DryRunAll)).To(Succeed())
+			return vs.Spec.Addr
+		}).Should(BeEmpty())
+	})
+
+	It("should sync a VaultSecret with the operator defaults", func() {
+		_, err := vaultClient.KVv2("secret").Put(ctx, "seeds/reconciler-defaults", map[string]any{"a": "1"})
+		Expect(err).ToNot(HaveOccurred())
+
+		vs := newWebhookVaultSecret("test-reconciler-defaults")
+		vs.Spec.Addr = ""
+		vs.Spec.Paths = []k8skiwicomv1.VaultSecretPath{{Path: "secret/seeds/reconciler-defaults"}}
+		Expect(k8sClient.Create(ctx, vs)).To(Succeed())
+		Expect(vs.Spec.Addr).To(BeEmpty())
+		Expect(vs.Spec.TargetSecretName).To(BeEmpty())
+		Expect(vs.Spec.TargetFormat).To(BeEmpty())
+		Expect(vs.Spec.ReconcilePeriod).To(BeEmpty())
+
+		var secret corev1.Secret
+		Eventually(func() error {
+			return k8sClient.Get(ctx, types.NamespacedName{Namespace: vs.Namespace, Name: vs.Name}, &secret)
+		}).Should(Succeed())
+		Expect(secret.Data).To(HaveKeyWithValue("a", []byte("1")))
+
+		// the defaults aren't written into the spec
+		Expect(k8sClient.Get(ctx, types.NamespacedName{Namespace: vs.Namespace, Name: vs.Name}, vs)).To(Succeed())
+		Expect(vs.Spec.Addr).To(BeEmpty())
+		Expect(meta.IsStatusConditionTrue(vs.Status.Conditions, k8skiwicomv1.ConditionReady)).To(BeTrue())
+	})
+})
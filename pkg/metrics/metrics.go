@@ -11,6 +11,9 @@ var (
 	prefix = "kw_vop_"
 	labels = []string{"namespace", "name", "error"}
 
+	authLabels        = []string{"method", "error"}
+	vaultSecretLabels = []string{"namespace", "name"}
+
 	ReconcileCount = prometheus.NewCounterVec(prometheus.CounterOpts{
 		Name: genMetricName("reconcile_count"),
 		Help: "Counter on how many times the reconcile loop has occur.",
@@ -21,10 +24,31 @@ var (
 		Help:    "Histogram on how much each reconcile loop lasts.",
 		Buckets: []float64{.1, .25, .5, 1, 2.5, 5, 10},
 	}, labels)
+
+	VaultLoginCount = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: genMetricName("vault_login_count"),
+		Help: "Counter on how many times the operator has logged in to Vault.",
+	}, authLabels)
+
+	VaultTokenRenewCount = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: genMetricName("vault_token_renew_count"),
+		Help: "Counter on how many times the operator has renewed a Vault token instead of logging in.",
+	}, authLabels)
+
+	AuthCacheSize = prometheus.NewGauge(prometheus.GaugeOpts{
+		Name: genMetricName("auth_cache_size"),
+		Help: "Gauge on how many Vault auth configurations are cached.",
+	})
+
+	DriftCorrectedCount = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: genMetricName("drift_corrected_count"),
+		Help: "Counter on how many times a target Secret changed outside of the operator has been restored.",
+	}, vaultSecretLabels)
 )
 
 func init() {
-	metrics.Registry.MustRegister(ReconcileCount, ReconcileDuration)
+	metrics.Registry.MustRegister(ReconcileCount, ReconcileDuration, VaultLoginCount, VaultTokenRenewCount, AuthCacheSize,
+		DriftCorrectedCount)
 }
 
 func genMetricName(n string) string {
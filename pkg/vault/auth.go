@@ -2,21 +2,37 @@ package vault
 
 import (
 	"context"
+	"crypto/tls"
+	"errors"
 	"fmt"
+	"net/http"
 	"os"
+	"strconv"
 	"sync"
 	"time"
 
 	vaultApi "github.com/hashicorp/vault/api"
 	authenticationv1 "k8s.io/api/authentication/v1"
+	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/client-go/kubernetes"
+
+	operatorMetrics "github.com/kiwicom/k8s-vault-operator/pkg/metrics"
 )
 
+// ErrAuth is returned when a Vault token can't be obtained.
+var ErrAuth = errors.New("authentication failed")
+
 type Tokener interface {
 	Token() (string, error)
 }
 
+// Revoker is implemented by Tokeners, which obtain their tokens by a login
+// and are able to revoke them once they are no longer used.
+type Revoker interface {
+	Revoke(ctx context.Context) error
+}
+
 type AuthToken struct {
 	token string
 }
@@ -29,61 +45,81 @@ func (a AuthToken) Token() (string, error) {
 	return a.token, nil
 }
 
-type AuthServiceAccount struct {
-	name               string
-	namespace          string
-	role               string
+// tokenCache keeps a Vault token obtained by a login call until it gets
+// close to its expiration. Renewable tokens are renewed with renew-self
+// until they reach their max TTL, a new login happens only afterwards
+// or when the renewal fails.
+type tokenCache struct {
+	method             string
 	refreshTokenBefore time.Duration
-	path               string
-	vaultClient        *vaultApi.Client
-	autoMount          bool
-	k8ClientSet        *kubernetes.Clientset
-	cacheMx            sync.RWMutex
+	cacheMx            sync.Mutex
 	cachedVaultToken   string
 	vaultTokenExpire   time.Time
+	vaultTokenTTL      time.Duration
+	renewable          bool
 }
 
-func NewAuthServiceAccount(vaultClient *vaultApi.Client, k8ClientSet *kubernetes.Clientset,
-	name, namespace, role, path string, automount bool, refreshTokenBefore time.Duration) *AuthServiceAccount {
-	return &AuthServiceAccount{
-		name:               name,
-		namespace:          namespace,
-		role:               role,
-		path:               path,
-		vaultClient:        vaultClient,
-		autoMount:          automount,
-		k8ClientSet:        k8ClientSet,
-		refreshTokenBefore: refreshTokenBefore,
-	}
-}
-func (a *AuthServiceAccount) cachedToken() string {
-	a.cacheMx.RLock()
-	defer a.cacheMx.RUnlock()
-	return a.cachedVaultToken
+func newTokenCache(method string, refreshTokenBefore time.Duration) tokenCache {
+	return tokenCache{method: method, refreshTokenBefore: refreshTokenBefore}
 }
 
-func (a *AuthServiceAccount) Token() (string, error) {
-	vaultToken := a.cachedToken()
-	if vaultToken != "" && time.Now().Add(a.refreshTokenBefore).Before(a.vaultTokenExpire) {
-		return vaultToken, nil
+// token returns the cached token, a renewed token or a token from a new login, in this order.
+func (c *tokenCache) token(vaultClient *vaultApi.Client, login func() (*vaultApi.Secret, error)) (string, error) {
+	c.cacheMx.Lock()
+	defer c.cacheMx.Unlock()
+
+	now := time.Now()
+	if c.cachedVaultToken != "" && now.Add(c.refreshTokenBefore).Before(c.vaultTokenExpire) {
+		return c.cachedVaultToken, nil
 	}
 
-	jwtToken, err := a.fetchJWT()
+	if c.cachedVaultToken != "" && c.renewable && now.Before(c.vaultTokenExpire) {
+		err := c.renew(vaultClient, now)
+		operatorMetrics.VaultTokenRenewCount.With(authMetricLabels(c.method, err)).Inc()
+		if err == nil {
+			return c.cachedVaultToken, nil
+		}
+	}
+
+	resp, err := login()
+	operatorMetrics.VaultLoginCount.With(authMetricLabels(c.method, err)).Inc()
 	if err != nil {
-		return "", fmt.Errorf("could not fetch JWT token: %w", err)
+		return "", err
 	}
 
-	data := map[string]any{
-		"role": a.role,
-		"jwt":  jwtToken,
+	return c.store(resp, now)
+}
+
+// renew extends the TTL of the cached token. Once Vault caps the TTL because of
+// the max TTL, the token is not renewed anymore and a new login will be done.
+func (c *tokenCache) renew(vaultClient *vaultApi.Client, issuedAt time.Time) error {
+	resp, err := vaultClient.Auth().Token().RenewTokenAsSelf(c.cachedVaultToken, int(c.vaultTokenTTL.Seconds()))
+	if err != nil {
+		return fmt.Errorf("failed to renew Vault token: %w", err)
 	}
 
-	t := time.Now()
-	resp, err := a.vaultClient.Logical().Write(a.path, data)
+	duration, err := resp.TokenTTL()
+	if err != nil {
+		return fmt.Errorf("could not read auth token TTL from response: %w", err)
+	}
+	if duration <= c.refreshTokenBefore {
+		c.renewable = false
+		return fmt.Errorf("renewed token TTL %s is too short", duration)
+	}
+
+	renewable, err := resp.TokenIsRenewable()
 	if err != nil {
-		return "", fmt.Errorf("failed to login to Vault with JWT: %w", err)
+		return fmt.Errorf("could not read auth token renewability from response: %w", err)
 	}
 
+	c.renewable = renewable && duration >= c.vaultTokenTTL
+	c.vaultTokenExpire = issuedAt.Add(duration)
+
+	return nil
+}
+
+// store reads the token from a login response and caches it.
+func (c *tokenCache) store(resp *vaultApi.Secret, issuedAt time.Time) (string, error) {
 	token, err := resp.TokenID()
 	if err != nil {
 		return "", fmt.Errorf("could not read auth token from response: %w", err)
@@ -93,16 +129,120 @@ func (a *AuthServiceAccount) Token() (string, error) {
 	if err != nil {
 		return "", fmt.Errorf("could not read auth token TTL from response: %w", err)
 	}
-	expireIn := t.Add(duration)
 
-	a.cacheMx.Lock()
-	defer a.cacheMx.Unlock()
-	a.cachedVaultToken = token
-	a.vaultTokenExpire = expireIn
+	renewable, err := resp.TokenIsRenewable()
+	if err != nil {
+		return "", fmt.Errorf("could not read auth token renewability from response: %w", err)
+	}
+
+	c.cachedVaultToken = token
+	c.vaultTokenExpire = issuedAt.Add(duration)
+	c.vaultTokenTTL = duration
+	c.renewable = renewable
 
 	return token, nil
 }
 
+// revoke revokes the cached token with revoke-self and clears the cache.
+func (c *tokenCache) revoke(ctx context.Context, vaultClient *vaultApi.Client) error {
+	c.cacheMx.Lock()
+	defer c.cacheMx.Unlock()
+
+	if c.cachedVaultToken == "" || !time.Now().Before(c.vaultTokenExpire) {
+		return nil
+	}
+
+	r := vaultClient.NewRequest(http.MethodPut, "/v1/auth/token/revoke-self")
+	r.ClientToken = c.cachedVaultToken
+	resp, err := vaultClient.RawRequestWithContext(ctx, r)
+	if resp != nil {
+		defer resp.Body.Close()
+	}
+	if err != nil {
+		return fmt.Errorf("failed to revoke Vault token: %w", err)
+	}
+
+	c.cachedVaultToken = ""
+	c.vaultTokenExpire = time.Time{}
+
+	return nil
+}
+
+func authMetricLabels(method string, err error) map[string]string {
+	return map[string]string{"method": method, "error": strconv.FormatBool(err != nil)}
+}
+
+type AuthServiceAccount struct {
+	tokenCache
+	name              string
+	namespace         string
+	role              string
+	path              string
+	vaultClient       *vaultApi.Client
+	autoMount         bool
+	k8ClientSet       *kubernetes.Clientset
+	audiences         []string
+	expirationSeconds *int64
+}
+
+func NewAuthServiceAccount(vaultClient *vaultApi.Client, k8ClientSet *kubernetes.Clientset,
+	name, namespace, role, path string, automount bool, refreshTokenBefore time.Duration) *AuthServiceAccount {
+	return &AuthServiceAccount{
+		tokenCache:  newTokenCache("kubernetes", refreshTokenBefore),
+		name:        name,
+		namespace:   namespace,
+		role:        role,
+		path:        path,
+		vaultClient: vaultClient,
+		autoMount:   automount,
+		k8ClientSet: k8ClientSet,
+	}
+}
+
+// NewAuthJWT returns AuthServiceAccount, which logs in to a Vault jwt auth mount
+// instead of the kubernetes one.
+func NewAuthJWT(vaultClient *vaultApi.Client, k8ClientSet *kubernetes.Clientset,
+	name, namespace, role, path string, refreshTokenBefore time.Duration) *AuthServiceAccount {
+	a := NewAuthServiceAccount(vaultClient, k8ClientSet, name, namespace, role, path, false, refreshTokenBefore)
+	a.method = "jwt"
+	return a
+}
+
+// WithTokenRequest sets audiences and expiration of Service Account tokens
+// requested from the Kubernetes API.
+func (a *AuthServiceAccount) WithTokenRequest(audiences []string, expirationSeconds *int64) *AuthServiceAccount {
+	a.audiences = audiences
+	a.expirationSeconds = expirationSeconds
+	return a
+}
+
+func (a *AuthServiceAccount) Token() (string, error) {
+	return a.token(a.vaultClient, a.login)
+}
+
+func (a *AuthServiceAccount) Revoke(ctx context.Context) error {
+	return a.revoke(ctx, a.vaultClient)
+}
+
+func (a *AuthServiceAccount) login() (*vaultApi.Secret, error) {
+	jwtToken, err := a.fetchJWT()
+	if err != nil {
+		return nil, fmt.Errorf("could not fetch JWT token: %w", err)
+	}
+
+	data := map[string]any{
+		"role": a.role,
+		"jwt":  jwtToken,
+	}
+
+	resp, err := a.vaultClient.Logical().Write(a.path, data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to login to Vault with JWT: %w", err)
+	}
+
+	return resp, nil
+}
+
 func (a *AuthServiceAccount) fetchJWT() (string, error) {
 	if a.autoMount {
 		return fetchJWTFromAutoMountedSecret()
@@ -117,6 +257,10 @@ func (a *AuthServiceAccount) fetchJWT() (string, error) {
 			Name:      a.name,
 			Namespace: a.namespace,
 		},
+		Spec: authenticationv1.TokenRequestSpec{
+			Audiences:         a.audiences,
+			ExpirationSeconds: a.expirationSeconds,
+		},
 	}
 
 	res, err := a.k8ClientSet.CoreV1().ServiceAccounts(a.namespace).
@@ -142,3 +286,183 @@ func fetchJWTFromAutoMountedSecret() (string, error) {
 
 	return string(token), nil
 }
+
+// AuthAppRole logs into Vault with AppRole credentials stored in a Kubernetes Secret.
+// The Secret is read on every login, so rotated credentials are picked up once the
+// cached Vault token expires.
+type AuthAppRole struct {
+	tokenCache
+	secretName  string
+	namespace   string
+	roleIDKey   string
+	secretIDKey string
+	path        string
+	vaultClient *vaultApi.Client
+	k8ClientSet *kubernetes.Clientset
+}
+
+func NewAuthAppRole(vaultClient *vaultApi.Client, k8ClientSet *kubernetes.Clientset,
+	secretName, namespace, roleIDKey, secretIDKey, path string, refreshTokenBefore time.Duration) *AuthAppRole {
+	return &AuthAppRole{
+		tokenCache:  newTokenCache("approle", refreshTokenBefore),
+		secretName:  secretName,
+		namespace:   namespace,
+		roleIDKey:   roleIDKey,
+		secretIDKey: secretIDKey,
+		path:        path,
+		vaultClient: vaultClient,
+		k8ClientSet: k8ClientSet,
+	}
+}
+
+func (a *AuthAppRole) Token() (string, error) {
+	return a.token(a.vaultClient, a.login)
+}
+
+func (a *AuthAppRole) Revoke(ctx context.Context) error {
+	return a.revoke(ctx, a.vaultClient)
+}
+
+func (a *AuthAppRole) login() (*vaultApi.Secret, error) {
+	roleID, secretID, err := a.fetchCredentials()
+	if err != nil {
+		return nil, fmt.Errorf("could not fetch AppRole credentials: %w", err)
+	}
+
+	data := map[string]any{
+		"role_id":   roleID,
+		"secret_id": secretID,
+	}
+
+	resp, err := a.vaultClient.Logical().Write(a.path, data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to login to Vault with AppRole: %w", err)
+	}
+
+	return resp, nil
+}
+
+func (a *AuthAppRole) fetchCredentials() (string, string, error) {
+	if a.k8ClientSet == nil {
+		return "", "", fmt.Errorf("not defined k8s clientset")
+	}
+
+	secret, err := a.k8ClientSet.CoreV1().Secrets(a.namespace).Get(context.TODO(), a.secretName, metav1.GetOptions{})
+	if err != nil {
+		return "", "", err
+	}
+
+	roleID, ok := secret.Data[a.roleIDKey]
+	if !ok || len(roleID) == 0 {
+		return "", "", fmt.Errorf("key %q not found in secret %q", a.roleIDKey, a.secretName)
+	}
+
+	// secret_id is optional, roles with bind_secret_id=false accept role_id only
+	return string(roleID), string(secret.Data[a.secretIDKey]), nil
+}
+
+// AuthCert logs into Vault with a TLS client certificate stored in a kubernetes.io/tls Secret.
+// The Secret is read on every login, so a rotated certificate is picked up once the
+// cached Vault token expires.
+type AuthCert struct {
+	tokenCache
+	secretName  string
+	namespace   string
+	name        string
+	path        string
+	vaultClient *vaultApi.Client
+	k8ClientSet *kubernetes.Clientset
+}
+
+func NewAuthCert(vaultClient *vaultApi.Client, k8ClientSet *kubernetes.Clientset,
+	secretName, namespace, name, path string, refreshTokenBefore time.Duration) *AuthCert {
+	return &AuthCert{
+		tokenCache:  newTokenCache("cert", refreshTokenBefore),
+		secretName:  secretName,
+		namespace:   namespace,
+		name:        name,
+		path:        path,
+		vaultClient: vaultClient,
+		k8ClientSet: k8ClientSet,
+	}
+}
+
+func (a *AuthCert) Token() (string, error) {
+	return a.token(a.vaultClient, a.login)
+}
+
+func (a *AuthCert) Revoke(ctx context.Context) error {
+	return a.revoke(ctx, a.vaultClient)
+}
+
+func (a *AuthCert) login() (*vaultApi.Secret, error) {
+	cert, err := a.fetchCertificate()
+	if err != nil {
+		return nil, fmt.Errorf("could not fetch client certificate: %w", err)
+	}
+
+	loginClient, err := clientWithCertificate(a.vaultClient, cert)
+	if err != nil {
+		return nil, err
+	}
+
+	data := map[string]any{}
+	if a.name != "" {
+		data["name"] = a.name
+	}
+
+	resp, err := loginClient.Logical().Write(a.path, data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to login to Vault with certificate: %w", err)
+	}
+
+	return resp, nil
+}
+
+func (a *AuthCert) fetchCertificate() (tls.Certificate, error) {
+	if a.k8ClientSet == nil {
+		return tls.Certificate{}, fmt.Errorf("not defined k8s clientset")
+	}
+
+	secret, err := a.k8ClientSet.CoreV1().Secrets(a.namespace).Get(context.TODO(), a.secretName, metav1.GetOptions{})
+	if err != nil {
+		return tls.Certificate{}, err
+	}
+
+	cert, err := tls.X509KeyPair(secret.Data[corev1.TLSCertKey], secret.Data[corev1.TLSPrivateKeyKey])
+	if err != nil {
+		return tls.Certificate{}, fmt.Errorf("invalid key pair in secret %q: %w", a.secretName, err)
+	}
+
+	return cert, nil
+}
+
+// clientWithCertificate returns a copy of the Vault client, which presents cert to the server.
+func clientWithCertificate(vaultClient *vaultApi.Client, cert tls.Certificate) (*vaultApi.Client, error) {
+	config := vaultClient.CloneConfig()
+
+	transport, ok := config.HttpClient.Transport.(*http.Transport)
+	if !ok {
+		return nil, fmt.Errorf("unsupported HTTPClient transport type %T", config.HttpClient.Transport)
+	}
+	transport = transport.Clone()
+	if transport.TLSClientConfig == nil {
+		transport.TLSClientConfig = &tls.Config{MinVersion: tls.VersionTLS12}
+	}
+	transport.TLSClientConfig.GetClientCertificate = func(*tls.CertificateRequestInfo) (*tls.Certificate, error) {
+		return &cert, nil
+	}
+
+	httpClient := *config.HttpClient
+	httpClient.Transport = transport
+	config.HttpClient = &httpClient
+
+	client, err := vaultApi.NewClient(config)
+	if err != nil {
+		return nil, fmt.Errorf("could not create Vault client: %w", err)
+	}
+	client.SetHeaders(vaultClient.Headers())
+	client.ClearToken()
+
+	return client, nil
+}
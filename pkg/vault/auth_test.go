@@ -0,0 +1,190 @@
+package vault
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"time"
+
+	vaultApi "github.com/hashicorp/vault/api"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+)
+
+// authMetric returns the value of an auth counter for method and error labels.
+func authMetric(name, method string, failed bool) float64 {
+	families, err := metrics.Registry.Gather()
+	Expect(err).ToNot(HaveOccurred())
+
+	errLabel := "false"
+	if failed {
+		errLabel = "true"
+	}
+	for _, family := range families {
+		if family.GetName() != name {
+			continue
+		}
+		for _, metric := range family.GetMetric() {
+			labels := make(map[string]string)
+			for _, label := range metric.GetLabel() {
+				labels[label.GetName()] = label.GetValue()
+			}
+			if labels["method"] == method && labels["error"] == errLabel {
+				return metric.GetCounter().GetValue()
+			}
+		}
+	}
+	return 0
+}
+
+func authSecret(token string, ttl time.Duration, renewable bool) *vaultApi.Secret {
+	return &vaultApi.Secret{Auth: &vaultApi.SecretAuth{
+		ClientToken:   token,
+		LeaseDuration: int(ttl.Seconds()),
+		Renewable:     renewable,
+	}}
+}
+
+var _ = Describe("Token cache", func() {
+	var (
+		server      *httptest.Server
+		client      *vaultApi.Client
+		renewCalls  atomic.Int32
+		renewStatus int
+		renewAuth   *vaultApi.SecretAuth
+		loginCalls  int
+		login       func() (*vaultApi.Secret, error)
+	)
+
+	BeforeEach(func() {
+		renewCalls.Store(0)
+		renewStatus = http.StatusOK
+		renewAuth = nil
+		loginCalls = 0
+		login = func() (*vaultApi.Secret, error) {
+			loginCalls++
+			return authSecret("login-token", 10*time.Minute, true), nil
+		}
+
+		server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+			if req.URL.Path != "/v1/auth/token/renew-self" {
+				w.WriteHeader(http.StatusNotFound)
+				return
+			}
+			renewCalls.Add(1)
+			if renewStatus != http.StatusOK {
+				w.WriteHeader(renewStatus)
+				_, _ = w.Write([]byte(`{"errors":["permission denied"]}`))
+				return
+			}
+			_ = json.NewEncoder(w).Encode(vaultApi.Secret{Auth: renewAuth})
+		}))
+		DeferCleanup(server.Close)
+
+		var err error
+		client, err = vaultApi.NewClient(&vaultApi.Config{Address: server.URL, MaxRetries: 0})
+		Expect(err).ToNot(HaveOccurred())
+	})
+
+	// expiringCache returns a cache with a cached token, which expires within refreshTokenBefore.
+	expiringCache := func(method string, renewable bool) *tokenCache {
+		cache := newTokenCache(method, 2*time.Minute)
+		_, err := cache.store(authSecret("cached-token", 10*time.Minute, renewable), time.Now().Add(-9*time.Minute))
+		Expect(err).ToNot(HaveOccurred())
+		return &cache
+	}
+
+	It("should return the cached token without calling Vault", func() {
+		cache := newTokenCache("test-cached", 2*time.Minute)
+		token, err := cache.token(client, login)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(token).To(Equal("login-token"))
+
+		token, err = cache.token(client, login)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(token).To(Equal("login-token"))
+		Expect(loginCalls).To(Equal(1))
+		Expect(renewCalls.Load()).To(BeZero())
+		Expect(authMetric("kw_vop_vault_login_count", "test-cached", false)).To(Equal(1.0))
+	})
+
+	It("should renew a renewable token instead of logging in", func() {
+		renewAuth = &vaultApi.SecretAuth{ClientToken: "cached-token", LeaseDuration: 600, Renewable: true}
+		cache := expiringCache("test-renew", true)
+
+		token, err := cache.token(client, login)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(token).To(Equal("cached-token"))
+		Expect(renewCalls.Load()).To(Equal(int32(1)))
+		Expect(loginCalls).To(BeZero())
+		Expect(cache.vaultTokenExpire).To(BeTemporally("~", time.Now().Add(10*time.Minute), time.Second))
+		Expect(cache.renewable).To(BeTrue())
+		Expect(authMetric("kw_vop_vault_token_renew_count", "test-renew", false)).To(Equal(1.0))
+		Expect(authMetric("kw_vop_vault_login_count", "test-renew", false)).To(BeZero())
+
+		// the renewed token is cached again
+		_, err = cache.token(client, login)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(renewCalls.Load()).To(Equal(int32(1)))
+	})
+
+	It("should stop renewing once the TTL is capped by the max TTL", func() {
+		renewAuth = &vaultApi.SecretAuth{ClientToken: "cached-token", LeaseDuration: 300, Renewable: true}
+		cache := expiringCache("test-capped", true)
+
+		token, err := cache.token(client, login)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(token).To(Equal("cached-token"))
+		Expect(cache.renewable).To(BeFalse())
+	})
+
+	It("should log in when the renewal fails", func() {
+		renewStatus = http.StatusForbidden
+		cache := expiringCache("test-renew-failed", true)
+
+		token, err := cache.token(client, login)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(token).To(Equal("login-token"))
+		Expect(renewCalls.Load()).To(Equal(int32(1)))
+		Expect(loginCalls).To(Equal(1))
+		Expect(authMetric("kw_vop_vault_token_renew_count", "test-renew-failed", true)).To(Equal(1.0))
+		Expect(authMetric("kw_vop_vault_login_count", "test-renew-failed", false)).To(Equal(1.0))
+	})
+
+	It("should log in when the renewed TTL is too short", func() {
+		renewAuth = &vaultApi.SecretAuth{ClientToken: "cached-token", LeaseDuration: 60, Renewable: true}
+		cache := expiringCache("test-renew-short", true)
+
+		token, err := cache.token(client, login)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(token).To(Equal("login-token"))
+		Expect(loginCalls).To(Equal(1))
+		Expect(authMetric("kw_vop_vault_token_renew_count", "test-renew-short", true)).To(Equal(1.0))
+	})
+
+	It("should log in without renewing a non-renewable token", func() {
+		cache := expiringCache("test-non-renewable", false)
+
+		token, err := cache.token(client, login)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(token).To(Equal("login-token"))
+		Expect(renewCalls.Load()).To(BeZero())
+		Expect(loginCalls).To(Equal(1))
+		Expect(authMetric("kw_vop_vault_token_renew_count", "test-non-renewable", false)).To(BeZero())
+		Expect(authMetric("kw_vop_vault_token_renew_count", "test-non-renewable", true)).To(BeZero())
+		Expect(authMetric("kw_vop_vault_login_count", "test-non-renewable", false)).To(Equal(1.0))
+	})
+
+	It("should count failed logins", func() {
+		login = func() (*vaultApi.Secret, error) {
+			return nil, ErrAuth
+		}
+		cache := newTokenCache("test-login-failed", 2*time.Minute)
+
+		_, err := cache.token(client, login)
+		Expect(err).To(MatchError(ErrAuth))
+		Expect(authMetric("kw_vop_vault_login_count", "test-login-failed", true)).To(Equal(1.0))
+	})
+})
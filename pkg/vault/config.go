@@ -10,6 +10,8 @@ import (
 	"github.com/knadh/koanf"
 	"github.com/knadh/koanf/providers/confmap"
 	"github.com/knadh/koanf/providers/env"
+
+	v1 "github.com/kiwicom/k8s-vault-operator/api/v1"
 )
 
 var k = koanf.New(".")
@@ -20,13 +22,30 @@ type AppConfig struct {
 	ClientMaxRetries        int           `koanf:"client_max_retries"`
 	DefaultSAAuthPath       string        `koanf:"default_sa_auth_path"`
 	DefaultSAName           string        `koanf:"default_sa_name"`
+	DefaultAppRoleAuthPath  string        `koanf:"default_approle_auth_path"`
+	DefaultCertAuthPath     string        `koanf:"default_cert_auth_path"`
+	DefaultJWTAuthPath      string        `koanf:"default_jwt_auth_path"`
 	DefaultReconcilePeriod  string        `koanf:"default_reconcile_period"`
 	OperatorRole            string        `koanf:"operator_role"`
 	Role                    string        `koanf:"role"`
 	DefaultVaultAddr        string        `koanf:"vault_addr"`
+	DefaultVaultNamespace   string        `koanf:"vault_namespace"`
 	VaultUIAddr             string        `koanf:"vault_ui_addr"`
 	MaxConcurrentReconciles int           `koanf:"max_concurrent_reconciles"`
 	RefreshTokenBefore      time.Duration `koanf:"refresh_token_before"`
+	AuthCacheIdleTimeout    time.Duration `koanf:"auth_cache_idle_timeout"`
+	CACert                  string        `koanf:"vault_cacert"`
+	CAPath                  string        `koanf:"vault_capath"`
+	ClientCert              string        `koanf:"vault_client_cert"`
+	ClientKey               string        `koanf:"vault_client_key"`
+	TLSServerName           string        `koanf:"vault_tls_server_name"`
+	SkipVerify              bool          `koanf:"vault_skip_verify"`
+	EnableWebhooks          bool          `koanf:"enable_webhooks"`
+	EnableDefaultingWebhook bool          `koanf:"enable_defaulting_webhook"`
+	DefaultPathsOptional    bool          `koanf:"default_paths_optional"`
+	MaxPathDepth            int           `koanf:"max_path_depth"`
+	MaxResolvedPaths        int           `koanf:"max_resolved_paths"`
+	MaxVaultRequests        int           `koanf:"max_vault_requests"`
 }
 
 func NewAppConfig() (AppConfig, error) {
@@ -35,11 +54,19 @@ func NewAppConfig() (AppConfig, error) {
 		"log_level":                 "INFO",
 		"default_sa_auth_path":      "",
 		"default_sa_name":           "vault-operator-sync",
+		"default_approle_auth_path": "auth/approle/login",
+		"default_cert_auth_path":    "auth/cert/login",
+		"default_jwt_auth_path":     "auth/jwt/login",
 		"default_reconcile_period":  "10m",
 		"operator_role":             "vault-operator",
 		"vault_addr":                "http://127.0.0.1:8200",
 		"max_concurrent_reconciles": 5,
 		"refresh_token_before":      time.Minute * 2,
+		"auth_cache_idle_timeout":   time.Hour,
+		"default_paths_optional":    true,
+		"max_path_depth":            10,
+		"max_resolved_paths":        500,
+		"max_vault_requests":        50,
 	}, "."), nil)
 	if err != nil {
 		return cfg, fmt.Errorf("default setting load: %w", err)
@@ -62,16 +89,62 @@ func NewAppConfig() (AppConfig, error) {
 	return cfg, nil
 }
 
+// VaultSecretDefaults returns defaults of VaultSecretSpec from the configuration.
+func (c AppConfig) VaultSecretDefaults() v1.VaultSecretDefaults {
+	return v1.VaultSecretDefaults{
+		Addr:            c.DefaultVaultAddr,
+		Namespace:       c.DefaultVaultNamespace,
+		ReconcilePeriod: c.DefaultReconcilePeriod,
+		SAName:          c.DefaultSAName,
+		SAAuthPath:      c.DefaultSAAuthPath,
+		Role:            c.Role,
+		AppRoleAuthPath: c.DefaultAppRoleAuthPath,
+		CertAuthPath:    c.DefaultCertAuthPath,
+		JWTAuthPath:     c.DefaultJWTAuthPath,
+	}
+}
+
 func NewClient(cfg AppConfig) (*vaultAPI.Client, error) {
-	operatorClient, err := vaultAPI.NewClient(&vaultAPI.Config{
-		Address:    cfg.DefaultVaultAddr,
+	operatorClient, err := NewClientForAddr(cfg, cfg.DefaultVaultAddr, nil)
+	if err != nil {
+		return nil, fmt.Errorf("could not initialize state vault client: %w", err)
+	}
+
+	return operatorClient, nil
+}
+
+// NewClientForAddr creates a Vault client for addr with TLS settings from AppConfig.
+// When caCert is set, it replaces the CA configured for the operator.
+func NewClientForAddr(cfg AppConfig, addr string, caCert []byte) (*vaultAPI.Client, error) {
+	config := &vaultAPI.Config{
+		Address:    addr,
 		MaxRetries: cfg.ClientMaxRetries,
 		Timeout:    cfg.ClientTimeout,
 		Backoff:    retryablehttp.LinearJitterBackoff,
-	})
+	}
+
+	tlsConfig := &vaultAPI.TLSConfig{
+		CACert:        cfg.CACert,
+		CAPath:        cfg.CAPath,
+		ClientCert:    cfg.ClientCert,
+		ClientKey:     cfg.ClientKey,
+		TLSServerName: cfg.TLSServerName,
+		Insecure:      cfg.SkipVerify,
+	}
+	if len(caCert) > 0 {
+		tlsConfig.CACert = ""
+		tlsConfig.CAPath = ""
+		tlsConfig.CACertBytes = caCert
+	}
+	// HttpClient is not set, so a new one is created and the TLS config isn't shared between clients
+	if err := config.ConfigureTLS(tlsConfig); err != nil {
+		return nil, fmt.Errorf("could not configure TLS: %w", err)
+	}
+
+	client, err := vaultAPI.NewClient(config)
 	if err != nil {
-		return nil, fmt.Errorf("could not initialize state vault client: %w", err)
+		return nil, err
 	}
 
-	return operatorClient, nil
+	return client, nil
 }
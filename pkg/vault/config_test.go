@@ -0,0 +1,55 @@
+package vault
+
+import (
+	"context"
+	"encoding/pem"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	v1 "github.com/kiwicom/k8s-vault-operator/api/v1"
+)
+
+var _ = Describe("Custom CA", func() {
+	var (
+		server *fakeVault
+		caCert []byte
+		spec   v1.VaultSecretSpec
+	)
+
+	BeforeEach(func() {
+		server = newFakeVaultTLS(map[string]map[string]any{
+			"secret/app/db": {"password": "secret"},
+		})
+		DeferCleanup(server.Close)
+		caCert = pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: server.Certificate().Raw})
+		spec = v1.VaultSecretSpec{Paths: []v1.VaultSecretPath{{Path: "secret/app/db"}}}
+	})
+
+	It("should verify the server certificate with the CA", func() {
+		client, err := NewClientForAddr(AppConfig{}, server.URL, caCert)
+		Expect(err).ToNot(HaveOccurred())
+		secret, err := client.Logical().Read("secret/data/app/db")
+		Expect(err).ToNot(HaveOccurred())
+		Expect(secret.Data).To(HaveKey("data"))
+
+		reader := newTestReader(server.URL, spec, WithCACert(caCert))
+		Expect(reader.ReadData(context.Background())).To(Succeed())
+		Expect(reader.GetData()).To(HaveKeyWithValue("password", "secret"))
+	})
+
+	It("should reject the server certificate without the CA", func() {
+		client, err := NewClientForAddr(AppConfig{}, server.URL, nil)
+		Expect(err).ToNot(HaveOccurred())
+		_, err = client.Logical().Read("secret/data/app/db")
+		Expect(err).To(MatchError(ContainSubstring("certificate signed by unknown authority")))
+
+		reader := newTestReader(server.URL, spec)
+		Expect(reader.ReadData(context.Background())).To(MatchError(ContainSubstring("certificate signed by unknown authority")))
+	})
+
+	It("should reject an invalid CA", func() {
+		_, err := NewClientForAddr(AppConfig{}, server.URL, []byte("not a certificate"))
+		Expect(err).To(HaveOccurred())
+	})
+})
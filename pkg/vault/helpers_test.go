@@ -0,0 +1,142 @@
+package vault
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/go-logr/logr"
+	. "github.com/onsi/gomega"
+
+	v1 "github.com/kiwicom/k8s-vault-operator/api/v1"
+)
+
+// fakeVault serves secrets of a KV v2 engine mounted at "secret/" and records namespaces of requests.
+type fakeVault struct {
+	*httptest.Server
+	secrets map[string]map[string]any
+
+	mx         sync.Mutex
+	namespaces map[string]string
+	// delay slows down responses, so concurrent requests overlap
+	delay       time.Duration
+	inFlight    int
+	maxInFlight int
+}
+
+func newFakeVault(secrets map[string]map[string]any) *fakeVault {
+	v := &fakeVault{secrets: secrets, namespaces: make(map[string]string)}
+	v.Server = httptest.NewServer(v)
+	return v
+}
+
+func newFakeVaultTLS(secrets map[string]map[string]any) *fakeVault {
+	v := &fakeVault{secrets: secrets, namespaces: make(map[string]string)}
+	v.Server = httptest.NewTLSServer(v)
+	return v
+}
+
+// maxConcurrentRequests returns the maximal number of requests served at once.
+func (v *fakeVault) maxConcurrentRequests() int {
+	v.mx.Lock()
+	defer v.mx.Unlock()
+	return v.maxInFlight
+}
+
+// namespace returns the X-Vault-Namespace header of the last request to the API path.
+func (v *fakeVault) namespace(apiPath string) (string, bool) {
+	v.mx.Lock()
+	defer v.mx.Unlock()
+	namespace, ok := v.namespaces[apiPath]
+	return namespace, ok
+}
+
+func (v *fakeVault) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	apiPath := strings.TrimPrefix(req.URL.Path, "/v1/")
+	v.mx.Lock()
+	v.namespaces[apiPath] = req.Header.Get("X-Vault-Namespace")
+	v.inFlight++
+	v.maxInFlight = max(v.maxInFlight, v.inFlight)
+	v.mx.Unlock()
+	defer func() {
+		v.mx.Lock()
+		v.inFlight--
+		v.mx.Unlock()
+	}()
+	time.Sleep(v.delay)
+
+	switch {
+	case strings.HasPrefix(apiPath, "sys/internal/ui/mounts/"):
+		writeData(w, map[string]any{"path": "secret/", "type": "kv", "options": map[string]any{"version": "2"}})
+	case strings.HasPrefix(apiPath, "auth/"):
+		_ = json.NewEncoder(w).Encode(map[string]any{
+			"auth": map[string]any{"client_token": "login-token", "lease_duration": 600, "renewable": true},
+		})
+	case strings.HasPrefix(apiPath, "secret/data/"):
+		secret, ok := v.secrets["secret/"+strings.TrimPrefix(apiPath, "secret/data/")]
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		writeData(w, map[string]any{"data": secret, "metadata": map[string]any{"version": 1}})
+	case strings.HasPrefix(apiPath, "secret/metadata/") && (req.Method == "LIST" || req.URL.Query().Get("list") == "true"):
+		keys := v.list("secret/" + strings.TrimPrefix(apiPath, "secret/metadata/"))
+		if len(keys) == 0 {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		writeData(w, map[string]any{"keys": keys})
+	default:
+		w.WriteHeader(http.StatusNotFound)
+	}
+}
+
+// list returns names of secrets and sub-directories in dir.
+func (v *fakeVault) list(dir string) []string {
+	dir = strings.TrimSuffix(dir, "/") + "/"
+	found := make(map[string]struct{})
+	for secretPath := range v.secrets {
+		rest, ok := strings.CutPrefix(secretPath, dir)
+		if !ok {
+			continue
+		}
+		if name, _, isDir := strings.Cut(rest, "/"); isDir {
+			found[name+"/"] = struct{}{}
+		} else {
+			found[name] = struct{}{}
+		}
+	}
+	keys := make([]string, 0, len(found))
+	for key := range found {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func writeData(w http.ResponseWriter, data map[string]any) {
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(map[string]any{"data": data})
+}
+
+func testConfig() *AppConfig {
+	return &AppConfig{
+		DefaultPathsOptional: true,
+		MaxPathDepth:         10,
+		MaxResolvedPaths:     500,
+	}
+}
+
+func newTestReader(addr string, spec v1.VaultSecretSpec, opts ...ReaderOption) *Reader {
+	spec.Addr = addr
+	if spec.ReconcilePeriod == "" {
+		spec.ReconcilePeriod = "10m"
+	}
+	reader, err := NewReader(NewAuthToken("testtoken"), &v1.VaultSecret{Spec: spec}, logr.Discard(), testConfig(), opts...)
+	Expect(err).ToNot(HaveOccurred())
+	return reader
+}
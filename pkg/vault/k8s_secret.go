@@ -4,15 +4,19 @@ import (
 	"context"
 	//nolint:gosec
 	"crypto/sha1"
+	"crypto/sha256"
 	"fmt"
 	"net/url"
+	"sort"
 	"strings"
 	"unicode"
+	"unicode/utf8"
 
 	"github.com/go-logr/logr"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	v1 "github.com/kiwicom/k8s-vault-operator/api/v1"
 )
@@ -50,6 +54,22 @@ func secretsAsEnv(logger logr.Logger, secret *v1.VaultSecret, secrets Data) (map
 	return output, nil
 }
 
+func secretsAsRaw(logger logr.Logger, secret *v1.VaultSecret, secrets Data) (map[string][]byte, error) {
+	rawSecrets, err := secrets.Raw(secret.Spec.GetSeparator())
+	if err != nil {
+		return nil, err
+	}
+
+	for key := range rawSecrets {
+		if !validateEnvKey(key) {
+			logger.Error(fmt.Errorf("invalid key %q", key), "invalid key", "key", key)
+			delete(rawSecrets, key)
+		}
+	}
+
+	return rawSecrets, nil
+}
+
 func secretsAsFile(secrets Data, format string) (map[string][]byte, error) {
 	var (
 		output   = map[string][]byte{}
@@ -125,11 +145,15 @@ func NewSecret(ctx context.Context, vaultSecret *v1.VaultSecret, data Data, uiBa
 	)
 	logger := ctrl.LoggerFrom(ctx)
 
-	switch format {
-	case TypeYaml, TypeJSON:
+	switch {
+	case len(vaultSecret.Spec.Templates) > 0:
+		contents, err = RenderTemplates(vaultSecret.Spec.Templates, data)
+	case format == TypeYaml || format == TypeJSON:
 		contents, err = secretsAsFile(data, format)
-	case TypeEnv:
+	case format == TypeEnv:
 		contents, err = secretsAsEnv(logger, vaultSecret, data)
+	case format == TypeRaw:
+		contents, err = secretsAsRaw(logger, vaultSecret, data)
 	default:
 		return nil, fmt.Errorf("invalid target format: %q", format)
 	}
@@ -138,6 +162,14 @@ func NewSecret(ctx context.Context, vaultSecret *v1.VaultSecret, data Data, uiBa
 		return nil, err
 	}
 
+	secretType := corev1.SecretType(vaultSecret.Spec.GetTargetType())
+	if secretType != corev1.SecretTypeOpaque {
+		contents, err = typedSecretData(secretType, vaultSecret.Spec.TargetKeys, contents)
+		if err != nil {
+			return nil, err
+		}
+	}
+
 	owner := vaultSecret.Name
 	if len(owner) > 63 {
 		//nolint:gosec
@@ -158,14 +190,19 @@ func NewSecret(ctx context.Context, vaultSecret *v1.VaultSecret, data Data, uiBa
 	if uiBaseAddr != "" && len(vaultSecret.Spec.Paths) > 0 {
 		var urls []string
 		for _, pathSpec := range vaultSecret.Spec.Paths {
+			uiPath := pathSpec.Path
+			if isPattern(uiPath) {
+				// link the literal part of a pattern as a recursive path
+				uiPath = patternBase(uiPath) + "*"
+			}
 			// Determine version for this path (remove trailing /* for lookup)
-			lookupPath := strings.TrimSuffix(strings.TrimSuffix(pathSpec.Path, "*"), "/")
+			lookupPath := strings.TrimSuffix(strings.TrimSuffix(uiPath, "*"), "/")
 			version := pathVersions[lookupPath]
 			if version == 0 {
 				// Fallback to KV2 if version not found
 				version = 2
 			}
-			url := buildVaultUIURL(uiBaseAddr, pathSpec.Path, version)
+			url := buildVaultUIURL(uiBaseAddr, uiPath, version)
 			urls = append(urls, url)
 		}
 		annotations["k8s-vault-operator/vault-ui-urls"] = strings.Join(urls, ", ")
@@ -178,7 +215,71 @@ func NewSecret(ctx context.Context, vaultSecret *v1.VaultSecret, data Data, uiBa
 			Labels:      labels,
 			Annotations: annotations,
 		},
-		Type: corev1.SecretTypeOpaque,
+		Type: secretType,
 		Data: contents,
 	}, nil
 }
+
+// NewConfigMap converts a Secret created by NewSecret to a ConfigMap with the same metadata and layout.
+// Values, which aren't valid UTF-8, are stored in BinaryData.
+func NewConfigMap(secret *corev1.Secret) *corev1.ConfigMap {
+	configMap := &corev1.ConfigMap{
+		ObjectMeta: *secret.ObjectMeta.DeepCopy(),
+		Data:       make(map[string]string, len(secret.Data)),
+	}
+	for key, value := range secret.Data {
+		if utf8.Valid(value) {
+			configMap.Data[key] = string(value)
+			continue
+		}
+		if configMap.BinaryData == nil {
+			configMap.BinaryData = make(map[string][]byte)
+		}
+		configMap.BinaryData[key] = value
+	}
+	return configMap
+}
+
+// NewTargetObject returns an empty object of the kind, which is synced into.
+func NewTargetObject(kind string) client.Object {
+	if kind == v1.TargetKindConfigMap {
+		return &corev1.ConfigMap{}
+	}
+	return &corev1.Secret{}
+}
+
+// ObjectData returns data of a Secret or a ConfigMap, nil for other objects.
+func ObjectData(obj client.Object) map[string][]byte {
+	switch o := obj.(type) {
+	case *corev1.Secret:
+		return o.Data
+	case *corev1.ConfigMap:
+		data := make(map[string][]byte, len(o.Data)+len(o.BinaryData))
+		for key, value := range o.Data {
+			data[key] = []byte(value)
+		}
+		for key, value := range o.BinaryData {
+			data[key] = value
+		}
+		return data
+	default:
+		return nil
+	}
+}
+
+// ContentHash returns a SHA-256 hash of Secret data, which doesn't depend on the order of keys.
+func ContentHash(data map[string][]byte) string {
+	keys := make([]string, 0, len(data))
+	for key := range data {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	h := sha256.New()
+	for _, key := range keys {
+		// length prefixes keep the encoding unambiguous for values containing any bytes
+		fmt.Fprintf(h, "%d:%s%d:", len(key), key, len(data[key]))
+		h.Write(data[key])
+	}
+	return fmt.Sprintf("%x", h.Sum(nil))
+}
@@ -0,0 +1,33 @@
+package vault
+
+import (
+	"context"
+
+	"golang.org/x/sync/semaphore"
+)
+
+// RequestLimiter limits the number of Vault requests in flight, it's shared by all Readers,
+// so many VaultSecrets with recursive paths can't overload Vault.
+type RequestLimiter struct {
+	sem *semaphore.Weighted
+}
+
+// NewRequestLimiter returns a limiter allowing max requests in flight, nil when max isn't positive.
+func NewRequestLimiter(maxRequests int) *RequestLimiter {
+	if maxRequests <= 0 {
+		return nil
+	}
+	return &RequestLimiter{sem: semaphore.NewWeighted(int64(maxRequests))}
+}
+
+// acquire blocks until a request can be sent, the returned func has to be called once the request is done.
+// A nil limiter doesn't limit anything.
+func (l *RequestLimiter) acquire(ctx context.Context) (func(), error) {
+	if l == nil {
+		return func() {}, nil
+	}
+	if err := l.sem.Acquire(ctx, 1); err != nil {
+		return nil, err
+	}
+	return func() { l.sem.Release(1) }, nil
+}
@@ -0,0 +1,32 @@
+package vault
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	v1 "github.com/kiwicom/k8s-vault-operator/api/v1"
+)
+
+var _ = Describe("Request limiter", func() {
+	It("should limit Vault requests in flight", func() {
+		secrets := make(map[string]map[string]any)
+		for i := range 10 {
+			secrets[fmt.Sprintf("secret/app/%d/db", i)] = map[string]any{"password": "secret"}
+		}
+		server := newFakeVault(secrets)
+		server.delay = 5 * time.Millisecond
+		DeferCleanup(server.Close)
+
+		reader := newTestReader(server.URL, v1.VaultSecretSpec{
+			Paths: []v1.VaultSecretPath{{Path: "secret/app/*"}},
+		}, WithRequestLimiter(NewRequestLimiter(2)))
+		Expect(reader.ReadData(context.Background())).To(Succeed())
+		Expect(reader.GetPathStatuses()).To(HaveLen(10))
+		Expect(server.maxConcurrentRequests()).To(BeNumerically("<=", 2))
+		Expect(server.maxConcurrentRequests()).To(BeNumerically(">", 1))
+	})
+})
@@ -0,0 +1,102 @@
+package vault
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	v1 "github.com/kiwicom/k8s-vault-operator/api/v1"
+)
+
+// ErrOverride is returned when more paths set the same key and the merge strategy is error.
+var ErrOverride = errors.New("override detected")
+
+// merger combines secrets of all paths into one Data tree according to the merge strategy.
+// Secrets have to be added in the order of spec.paths, so firstWins and lastWins are deterministic.
+type merger struct {
+	strategy string
+	root     Data
+	// sources maps key paths of values and nodes to the Vault path, which has added them
+	sources map[string]string
+}
+
+func newMerger(strategy string) *merger {
+	return &merger{
+		strategy: strategy,
+		root:     make(Data),
+		sources:  make(map[string]string),
+	}
+}
+
+// add adds secrets read from the Vault path source to the node at nodePath.
+func (m *merger) add(source string, nodePath []string, secrets Secrets) error {
+	node := m.root
+	for i, name := range nodePath {
+		keyPath := strings.Join(nodePath[:i+1], "/")
+
+		existing, ok := node[name]
+		if !ok {
+			child := make(Data)
+			node[name] = child
+			m.sources[keyPath] = source
+			node = child
+			continue
+		}
+		if child, isNode := existing.(Data); isNode {
+			node = child
+			continue
+		}
+
+		// the key of a value is used as a node by this path
+		keep, err := m.resolve(keyPath, source)
+		if err != nil || keep {
+			return err
+		}
+		child := make(Data)
+		node[name] = child
+		m.sources[keyPath] = source
+		node = child
+	}
+
+	keys := make([]string, 0, len(secrets))
+	for key := range secrets {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		keyPath := strings.Join(append(nodePath[:len(nodePath):len(nodePath)], key), "/")
+		if _, ok := node[key]; ok {
+			keep, err := m.resolve(keyPath, source)
+			if err != nil {
+				return err
+			}
+			if keep {
+				continue
+			}
+		}
+		node[key] = secrets[key]
+		m.sources[keyPath] = source
+	}
+
+	return nil
+}
+
+// resolve decides a conflict on keyPath, it reports whether the existing value is kept.
+func (m *merger) resolve(keyPath, source string) (bool, error) {
+	switch m.strategy {
+	case v1.MergeStrategyFirstWins:
+		return true, nil
+	case v1.MergeStrategyLastWins:
+		// drop sources of the replaced node, if any
+		for key := range m.sources {
+			if strings.HasPrefix(key, keyPath+"/") {
+				delete(m.sources, key)
+			}
+		}
+		return false, nil
+	default:
+		return false, fmt.Errorf("%w: key %q from %q is already used by %q", ErrOverride, keyPath, source, m.sources[keyPath])
+	}
+}
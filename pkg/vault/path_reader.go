@@ -2,8 +2,10 @@ package vault
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
+	"strconv"
 	"time"
 
 	"github.com/go-logr/logr"
@@ -14,44 +16,98 @@ type PathReader struct {
 	Client          *api.Client
 	log             logr.Logger
 	reconcilePeriod time.Duration
+	limiter         *RequestLimiter
 }
 
 var (
 	ErrNotFound = errors.New("path doesn't exist")
 	ErrEmpty    = errors.New("path is empty")
+	// ErrMissingPaths is returned when paths, which aren't optional, don't exist or are empty
+	ErrMissingPaths = errors.New("required paths are missing or empty")
 )
 
-func (r *PathReader) Read(ctx context.Context, path string) (map[string]any, int, error) {
-	mountPath, version, err := kvPreflightVersionRequest(ctx, r.Client, path)
+// ReadResult holds secrets read from a Vault path.
+type ReadResult struct {
+	Data map[string]any
+	// KVVersion is the version of the KV secrets engine (1 or 2)
+	KVVersion int
+	// Version is the version of the secret that was read, it's always 0 for KV1
+	Version int
+}
+
+// Read reads secrets from path. For KV2, a non-zero version reads that specific
+// version of the secret instead of the latest one.
+func (r *PathReader) Read(ctx context.Context, path string, version int) (*ReadResult, error) {
+	// each request holds its own slot of the limiter
+	release, err := r.limiter.acquire(ctx)
+	if err != nil {
+		return nil, err
+	}
+	mountPath, kvVersion, err := kvPreflightVersionRequest(ctx, r.Client, path)
+	release()
 
 	if err != nil {
-		return nil, 0, err
+		return nil, err
 	}
 
-	switch version {
+	var params map[string]string
+	switch kvVersion {
 	case 1:
+		if version != 0 {
+			return nil, fmt.Errorf("version of %q is pinned, but KV v1 secrets are not versioned", path)
+		}
 	case 2:
 		path = addPrefixToKVPath(path, mountPath, "data")
+		if version != 0 {
+			params = map[string]string{"version": strconv.Itoa(version)}
+		}
 	default:
-		return nil, 0, fmt.Errorf("unsupported secret engine version %d", version)
+		return nil, fmt.Errorf("unsupported secret engine version %d", kvVersion)
 	}
 
-	secret, err := kvReadRequest(ctx, r.Client, path, nil)
+	release, err = r.limiter.acquire(ctx)
+	if err != nil {
+		return nil, err
+	}
+	secret, err := kvReadRequest(ctx, r.Client, path, params)
+	release()
 
 	if err != nil {
-		return nil, 0, err
+		return nil, err
 	}
 
 	if secret == nil {
-		return nil, 0, fmt.Errorf("%w: %s", ErrEmpty, path)
+		return nil, fmt.Errorf("%w: %s", ErrEmpty, path)
 	}
 
-	if version == 2 {
+	if kvVersion == 2 {
 		if data, ok := secret.Data["data"]; ok && data != nil {
-			return data.(map[string]any), version, nil
+			return &ReadResult{
+				Data:      data.(map[string]any),
+				KVVersion: kvVersion,
+				Version:   secretVersion(secret.Data["metadata"]),
+			}, nil
 		}
-		return nil, 0, fmt.Errorf("%w: %s", ErrEmpty, path)
+		return nil, fmt.Errorf("%w: %s", ErrEmpty, path)
 	}
 
-	return secret.Data, version, nil
+	return &ReadResult{Data: secret.Data, KVVersion: kvVersion}, nil
+}
+
+// secretVersion reads the version from KV2 secret metadata.
+func secretVersion(metadata any) int {
+	m, ok := metadata.(map[string]any)
+	if !ok {
+		return 0
+	}
+
+	switch v := m["version"].(type) {
+	case json.Number:
+		version, _ := v.Int64()
+		return int(version)
+	case float64:
+		return int(v)
+	default:
+		return 0
+	}
 }
@@ -0,0 +1,304 @@
+package vault
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"path"
+	"sort"
+	"strings"
+	"sync"
+
+	"golang.org/x/sync/errgroup"
+)
+
+// ErrPathLimit is returned when recursive paths or path patterns exceed the configured limits.
+var ErrPathLimit = errors.New("path limit exceeded")
+
+const patternMeta = "*?[{"
+
+// splitRecursive returns the path without a trailing "*", which makes the path recursive.
+// A trailing "**" is a pattern matching secrets at any depth instead.
+func splitRecursive(p string) (string, bool) {
+	if strings.HasSuffix(p, "*") && !strings.HasSuffix(p, "**") {
+		return strings.TrimSuffix(p, "*"), true
+	}
+	return p, false
+}
+
+// isPattern reports whether the path without a trailing "*" contains glob patterns.
+func isPattern(p string) bool {
+	base, _ := splitRecursive(p)
+	return strings.ContainsAny(base, patternMeta)
+}
+
+// patternBase returns the literal directory of a pattern, the part before the first segment with a glob pattern.
+func patternBase(pattern string) string {
+	segments := strings.Split(pattern, "/")
+	for i, segment := range segments {
+		if strings.ContainsAny(segment, patternMeta) {
+			return strings.Join(segments[:i], "/") + "/"
+		}
+	}
+	return pattern
+}
+
+// expandBraces expands {a,b} alternatives, e.g. "apps/{api,worker}/db" to "apps/api/db" and "apps/worker/db".
+func expandBraces(pattern string) []string {
+	start := strings.IndexByte(pattern, '{')
+	if start < 0 {
+		return []string{pattern}
+	}
+	end := strings.IndexByte(pattern[start:], '}')
+	if end < 0 {
+		return []string{pattern}
+	}
+	end += start
+
+	var expanded []string
+	for _, alternative := range strings.Split(pattern[start+1:end], ",") {
+		expanded = append(expanded, expandBraces(pattern[:start]+alternative+pattern[end+1:])...)
+	}
+	return expanded
+}
+
+// pathLimits protect Vault from recursive paths and patterns resolving to huge trees.
+type pathLimits struct {
+	// maxDepth is the maximal number of directory levels below a recursive path or matched by "**"
+	maxDepth int
+	// maxPaths is the maximal number of paths a path resolves to
+	maxPaths int
+}
+
+// newPathLimits returns limits of the operator configuration, limits that aren't positive are unlimited.
+func newPathLimits(maxDepth, maxPaths int) pathLimits {
+	limits := pathLimits{maxDepth: maxDepth, maxPaths: maxPaths}
+	if limits.maxDepth <= 0 {
+		limits.maxDepth = math.MaxInt
+	}
+	if limits.maxPaths <= 0 {
+		limits.maxPaths = math.MaxInt
+	}
+	return limits
+}
+
+// expandPattern resolves a path pattern to absolute paths of secrets by listing Vault.
+// Only existing secrets are matched, except for alternatives of braces without any other pattern.
+func (r *Reader) expandPattern(ctx context.Context, pattern string, limits pathLimits) ([]string, error) {
+	base, recursive := splitRecursive(pattern)
+
+	found := make(map[string]struct{})
+	for _, alternative := range expandBraces(base) {
+		paths, err := r.expandAlternative(ctx, alternative, recursive, limits)
+		if err != nil {
+			return nil, fmt.Errorf("expand %q: %w", pattern, err)
+		}
+		for _, p := range paths {
+			found[p] = struct{}{}
+		}
+		if len(found) > limits.maxPaths {
+			return nil, fmt.Errorf("%w: %q resolves to more than %d paths", ErrPathLimit, pattern, limits.maxPaths)
+		}
+	}
+
+	paths := make([]string, 0, len(found))
+	for p := range found {
+		paths = append(paths, p)
+	}
+	sort.Strings(paths)
+	return paths, nil
+}
+
+func (r *Reader) expandAlternative(ctx context.Context, alternative string, recursive bool, limits pathLimits) ([]string, error) {
+	if !strings.ContainsAny(alternative, patternMeta) {
+		if recursive {
+			return r.getPathsRecursive(ctx, alternative, limits)
+		}
+		return []string{alternative}, nil
+	}
+
+	segments := strings.Split(alternative, "/")
+	// the last segment is empty for recursive paths, they end with "/"
+	last := segments[len(segments)-1]
+	dirs, err := r.matchDirs(ctx, segments[:len(segments)-1], limits)
+	if err != nil {
+		return nil, err
+	}
+	if len(dirs) == 0 {
+		return nil, nil
+	}
+
+	var paths []string
+	switch {
+	case recursive:
+		for _, dir := range dirs {
+			subPaths, err := r.getPathsRecursive(ctx, dir, limits)
+			if err != nil {
+				return nil, err
+			}
+			paths = append(paths, subPaths...)
+		}
+	case last == "**":
+		_, paths, err = r.walkDirs(ctx, dirs, limits)
+	default:
+		var leaves []string
+		_, leaves, err = r.listDirs(ctx, dirs)
+		for _, leaf := range leaves {
+			if ok, _ := path.Match(last, path.Base(leaf)); ok {
+				paths = append(paths, leaf)
+			}
+		}
+	}
+	return paths, err
+}
+
+// matchDirs resolves directory segments of a pattern to existing directories, each of them ends with "/".
+func (r *Reader) matchDirs(ctx context.Context, segments []string, limits pathLimits) ([]string, error) {
+	dirs := []string{""}
+	for _, segment := range segments {
+		var (
+			next []string
+			err  error
+		)
+		switch {
+		case segment == "**":
+			next, _, err = r.walkDirs(ctx, dirs, limits)
+		case strings.ContainsAny(segment, patternMeta):
+			var subDirs []string
+			subDirs, _, err = r.listDirs(ctx, dirs)
+			for _, subDir := range subDirs {
+				if ok, _ := path.Match(segment, path.Base(subDir)); ok {
+					next = append(next, subDir)
+				}
+			}
+		default:
+			for _, dir := range dirs {
+				next = append(next, dir+segment+"/")
+			}
+		}
+		if err != nil {
+			return nil, err
+		}
+		if len(next) > limits.maxPaths {
+			return nil, fmt.Errorf("%w: more than %d directories match %q", ErrPathLimit, limits.maxPaths, segment)
+		}
+		if len(next) == 0 {
+			// nothing matches, the remaining segments don't need to be listed
+			return nil, nil
+		}
+		dirs = next
+	}
+	return dirs, nil
+}
+
+// walkDirs returns dirs with all their sub-directories and all secrets in them, up to limits.maxDepth levels below dirs.
+func (r *Reader) walkDirs(ctx context.Context, dirs []string, limits pathLimits) ([]string, []string, error) {
+	if len(dirs) == 0 {
+		return nil, nil, nil
+	}
+	root := dirs[0]
+	allDirs := append([]string(nil), dirs...)
+	var leaves []string
+	for depth := 0; len(dirs) > 0; depth++ {
+		subDirs, dirLeaves, err := r.listDirs(ctx, dirs)
+		if err != nil {
+			return nil, nil, err
+		}
+		leaves = append(leaves, dirLeaves...)
+		if len(subDirs) > 0 && depth == limits.maxDepth {
+			return nil, nil, fmt.Errorf("%w: sub-paths of %q are deeper than %d levels", ErrPathLimit, root, limits.maxDepth)
+		}
+		allDirs = append(allDirs, subDirs...)
+		if len(leaves) > limits.maxPaths || len(allDirs) > limits.maxPaths {
+			return nil, nil, fmt.Errorf("%w: more than %d paths found", ErrPathLimit, limits.maxPaths)
+		}
+		dirs = subDirs
+	}
+	return allDirs, leaves, nil
+}
+
+// listDirs lists dirs concurrently and returns their sub-directories and secrets as absolute paths.
+func (r *Reader) listDirs(ctx context.Context, dirs []string) ([]string, []string, error) {
+	var (
+		mx      sync.Mutex
+		subDirs []string
+		leaves  []string
+	)
+	wg, gCtx := errgroup.WithContext(ctx)
+	wg.SetLimit(10)
+	for _, dir := range dirs {
+		wg.Go(func() error {
+			keys, err := r.list(gCtx, dir)
+			if err != nil {
+				return err
+			}
+			mx.Lock()
+			defer mx.Unlock()
+			for _, key := range keys {
+				if strings.HasSuffix(key, "/") {
+					subDirs = append(subDirs, dir+key)
+				} else {
+					leaves = append(leaves, dir+key)
+				}
+			}
+			return nil
+		})
+	}
+	if err := wg.Wait(); err != nil {
+		return nil, nil, err
+	}
+	sort.Strings(subDirs)
+	sort.Strings(leaves)
+	return subDirs, leaves, nil
+}
+
+// list returns keys of the directory, it's empty when the directory doesn't exist.
+func (r *Reader) list(ctx context.Context, dir string) ([]string, error) {
+	// each request holds its own slot of the limiter
+	release, err := r.limiter.acquire(ctx)
+	if err != nil {
+		return nil, err
+	}
+	mountPath, version, err := kvPreflightVersionRequest(ctx, r.client, dir)
+	release()
+	if err != nil {
+		return nil, err
+	}
+
+	if version != 1 && version != 2 {
+		return nil, fmt.Errorf("unsupported engine for recursion, expected 1 or 2, got %d", version)
+	}
+	apiPath := dir
+	if version == 2 {
+		apiPath = addPrefixToKVPath(dir, mountPath, "metadata")
+	}
+
+	release, err = r.limiter.acquire(ctx)
+	if err != nil {
+		return nil, err
+	}
+	secret, err := r.client.Logical().ListWithContext(ctx, apiPath)
+	release()
+	if err != nil {
+		return nil, fmt.Errorf("could not list Vault path %q: %w", apiPath, err)
+	}
+	if secret == nil {
+		return nil, nil
+	}
+
+	values, ok := secret.Data["keys"].([]any)
+	if !ok {
+		return nil, fmt.Errorf("cannot cast keys to slice of interfaces at path: %q", apiPath)
+	}
+
+	keys := make([]string, 0, len(values))
+	for _, value := range values {
+		key, ok := value.(string)
+		if !ok {
+			return nil, fmt.Errorf("cannot cast keys to string at path: %q", apiPath)
+		}
+		keys = append(keys, key)
+	}
+	return keys, nil
+}
@@ -0,0 +1,89 @@
+package vault
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	v1 "github.com/kiwicom/k8s-vault-operator/api/v1"
+)
+
+var _ = Describe("Path patterns", func() {
+	var (
+		server *fakeVault
+		limits pathLimits
+	)
+
+	BeforeEach(func() {
+		server = newFakeVault(map[string]map[string]any{
+			"secret/kv/a/db":       {"password": "secret"},
+			"secret/kv/a/cache/db": {"password": "cache"},
+		})
+		DeferCleanup(server.Close)
+		limits = pathLimits{maxDepth: 10, maxPaths: 500}
+	})
+
+	DescribeTable("should expand patterns to existing secrets",
+		func(pattern string, expected []string) {
+			reader := newTestReader(server.URL, v1.VaultSecretSpec{})
+			paths, err := reader.expandPattern(context.Background(), pattern, limits)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(paths).To(Equal(expected))
+		},
+		Entry("any depth", "secret/kv/a*/**", []string{"secret/kv/a/cache/db", "secret/kv/a/db"}),
+		Entry("single segment", "secret/kv/*/db", []string{"secret/kv/a/db"}),
+		Entry("no match before any depth", "secret/kv/x*/**", []string{}),
+		Entry("no match before a segment", "secret/kv/x*/db", []string{}),
+		Entry("no match before a directory pattern", "secret/kv/x*/**/db", []string{}),
+		Entry("no match of a recursive path", "secret/kv/x*/*", []string{}),
+	)
+
+	It("should read nothing from an optional pattern without matches", func() {
+		reader := newTestReader(server.URL, v1.VaultSecretSpec{
+			Paths: []v1.VaultSecretPath{{Path: "secret/kv/x*/**"}},
+		})
+		Expect(reader.ReadData(context.Background())).To(Succeed())
+		Expect(reader.GetData()).To(BeEmpty())
+	})
+})
+
+var _ = Describe("Path limits", func() {
+	var server *fakeVault
+
+	BeforeEach(func() {
+		server = newFakeVault(map[string]map[string]any{
+			"secret/kv/a/db":       {"password": "secret"},
+			"secret/kv/a/cache/db": {"password": "cache"},
+		})
+		DeferCleanup(server.Close)
+	})
+
+	newReader := func(maxPathDepth, maxResolvedPaths int, path v1.VaultSecretPath) *Reader {
+		reader := newTestReader(server.URL, v1.VaultSecretSpec{Paths: []v1.VaultSecretPath{path}})
+		reader.cfg.MaxPathDepth = maxPathDepth
+		reader.cfg.MaxResolvedPaths = maxResolvedPaths
+		return reader
+	}
+
+	It("should not limit paths when the limits aren't positive", func() {
+		for _, limit := range []int{0, -1} {
+			reader := newReader(limit, limit, v1.VaultSecretPath{Path: "secret/kv/**"})
+			Expect(reader.ReadData(context.Background())).To(Succeed())
+			Expect(reader.GetPathStatuses()).To(HaveLen(2))
+		}
+	})
+
+	It("should fail when the limits are exceeded", func() {
+		reader := newReader(1, 500, v1.VaultSecretPath{Path: "secret/kv/**"})
+		Expect(reader.ReadData(context.Background())).To(MatchError(ErrPathLimit))
+
+		reader = newReader(10, 1, v1.VaultSecretPath{Path: "secret/kv/**"})
+		Expect(reader.ReadData(context.Background())).To(MatchError(ErrPathLimit))
+	})
+
+	It("should limit the depth of a path without the operator limit", func() {
+		reader := newReader(0, 0, v1.VaultSecretPath{Path: "secret/kv/**", MaxDepth: 1})
+		Expect(reader.ReadData(context.Background())).To(MatchError(ErrPathLimit))
+	})
+})
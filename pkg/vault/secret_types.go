@@ -0,0 +1,92 @@
+package vault
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+)
+
+// Keys of synced data used to generate a .dockerconfigjson
+const (
+	dockerRegistryKey = "registry"
+	dockerUsernameKey = "username"
+	dockerPasswordKey = "password"
+)
+
+// requiredKeys lists keys, which have to be present in a Secret of the type.
+var requiredKeys = map[corev1.SecretType][]string{
+	corev1.SecretTypeTLS:     {corev1.TLSCertKey, corev1.TLSPrivateKeyKey},
+	corev1.SecretTypeSSHAuth: {corev1.SSHAuthPrivateKey},
+}
+
+// typedSecretData renames keys of data according to keyMap, which maps keys of the Secret type to keys
+// of the synced data, and checks all keys required by the type are present.
+func typedSecretData(secretType corev1.SecretType, keyMap map[string]string, data map[string][]byte) (map[string][]byte, error) {
+	output := make(map[string][]byte, len(data))
+	for key, value := range data {
+		output[key] = value
+	}
+	// mapped keys are removed before the renamed ones are added, so keys can be swapped
+	for targetKey, sourceKey := range keyMap {
+		if _, ok := data[sourceKey]; !ok {
+			return nil, fmt.Errorf("key %q mapped to %q is missing in synced data", sourceKey, targetKey)
+		}
+		delete(output, sourceKey)
+	}
+	for targetKey, sourceKey := range keyMap {
+		output[targetKey] = data[sourceKey]
+	}
+
+	switch secretType {
+	case corev1.SecretTypeDockerConfigJson:
+		if _, ok := output[corev1.DockerConfigJsonKey]; !ok {
+			dockerConfig, err := dockerConfigJSON(output)
+			if err != nil {
+				return nil, err
+			}
+			delete(output, dockerRegistryKey)
+			delete(output, dockerUsernameKey)
+			delete(output, dockerPasswordKey)
+			output[corev1.DockerConfigJsonKey] = dockerConfig
+		}
+	case corev1.SecretTypeBasicAuth:
+		if _, ok := output[corev1.BasicAuthUsernameKey]; ok {
+			break
+		}
+		if _, ok := output[corev1.BasicAuthPasswordKey]; ok {
+			break
+		}
+		return nil, fmt.Errorf("secret of type %q requires key %q or %q", secretType, corev1.BasicAuthUsernameKey, corev1.BasicAuthPasswordKey)
+	}
+
+	for _, key := range requiredKeys[secretType] {
+		if _, ok := output[key]; !ok {
+			return nil, fmt.Errorf("secret of type %q requires key %q", secretType, key)
+		}
+	}
+
+	return output, nil
+}
+
+// dockerConfigJSON generates a .dockerconfigjson from registry, username and password keys of data.
+func dockerConfigJSON(data map[string][]byte) ([]byte, error) {
+	for _, key := range []string{dockerRegistryKey, dockerUsernameKey, dockerPasswordKey} {
+		if _, ok := data[key]; !ok {
+			return nil, fmt.Errorf("secret of type %q requires key %q or keys %q, %q and %q",
+				corev1.SecretTypeDockerConfigJson, corev1.DockerConfigJsonKey, dockerRegistryKey, dockerUsernameKey, dockerPasswordKey)
+		}
+	}
+
+	username, password := string(data[dockerUsernameKey]), string(data[dockerPasswordKey])
+	return json.Marshal(map[string]any{
+		"auths": map[string]any{
+			string(data[dockerRegistryKey]): map[string]string{
+				"username": username,
+				"password": password,
+				"auth":     base64.StdEncoding.EncodeToString([]byte(username + ":" + password)),
+			},
+		},
+	})
+}
@@ -0,0 +1,52 @@
+package vault
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+)
+
+var _ = Describe("Typed Secret data", func() {
+	It("should swap mapped keys", func() {
+		data := map[string][]byte{
+			corev1.TLSCertKey:       []byte("key"),
+			corev1.TLSPrivateKeyKey: []byte("cert"),
+		}
+		keyMap := map[string]string{
+			corev1.TLSCertKey:       corev1.TLSPrivateKeyKey,
+			corev1.TLSPrivateKeyKey: corev1.TLSCertKey,
+		}
+
+		// the map order differs between runs
+		for range 20 {
+			output, err := typedSecretData(corev1.SecretTypeTLS, keyMap, data)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(output).To(Equal(map[string][]byte{
+				corev1.TLSCertKey:       []byte("cert"),
+				corev1.TLSPrivateKeyKey: []byte("key"),
+			}))
+		}
+		Expect(data).To(HaveKeyWithValue(corev1.TLSCertKey, []byte("key")))
+	})
+
+	It("should rename a mapped key", func() {
+		data := map[string][]byte{
+			"cert":                  []byte("cert"),
+			corev1.TLSPrivateKeyKey: []byte("key"),
+			"other":                 []byte("other"),
+		}
+
+		output, err := typedSecretData(corev1.SecretTypeTLS, map[string]string{corev1.TLSCertKey: "cert"}, data)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(output).To(Equal(map[string][]byte{
+			corev1.TLSCertKey:       []byte("cert"),
+			corev1.TLSPrivateKeyKey: []byte("key"),
+			"other":                 []byte("other"),
+		}))
+	})
+
+	It("should fail on a missing mapped key", func() {
+		_, err := typedSecretData(corev1.SecretTypeTLS, map[string]string{corev1.TLSCertKey: "cert"}, map[string][]byte{})
+		Expect(err).To(MatchError(ContainSubstring(`key "cert" mapped to "tls.crt" is missing`)))
+	})
+})
@@ -0,0 +1,187 @@
+package vault
+
+import (
+	"bytes"
+	//nolint:gosec
+	"crypto/sha1"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"reflect"
+	"sort"
+	"strings"
+	"text/template"
+
+	"gopkg.in/yaml.v3"
+)
+
+// maxTemplateSize limits the rendered output of a template to the maximum size of a Secret.
+const maxTemplateSize = 1 << 20
+
+// templateFuncs are helpers available in templates. They are limited to pure functions,
+// so templates can't read files or environment of the operator.
+var templateFuncs = template.FuncMap{
+	"b64enc":    func(v any) string { return base64.StdEncoding.EncodeToString([]byte(toString(v))) },
+	"b64dec":    b64dec,
+	"toJson":    toJSON,
+	"toYaml":    toYaml,
+	"default":   defaultValue,
+	"required":  required,
+	"quote":     func(v any) string { return fmt.Sprintf("%q", toString(v)) },
+	"trim":      func(v any) string { return strings.TrimSpace(toString(v)) },
+	"upper":     func(v any) string { return strings.ToUpper(toString(v)) },
+	"lower":     func(v any) string { return strings.ToLower(toString(v)) },
+	"replace":   func(old, replacement string, v any) string { return strings.ReplaceAll(toString(v), old, replacement) },
+	"join":      join,
+	"indent":    func(spaces int, v any) string { return indent(spaces, toString(v)) },
+	"nindent":   func(spaces int, v any) string { return "\n" + indent(spaces, toString(v)) },
+	"keys":      keys,
+	"sha256sum": func(v any) string { return fmt.Sprintf("%x", sha256.Sum256([]byte(toString(v)))) },
+	"htpasswd":  htpasswd,
+}
+
+// RenderTemplates renders templates over data, the result maps keys of templates to rendered contents.
+func RenderTemplates(templates map[string]string, data Data) (map[string][]byte, error) {
+	output := make(map[string][]byte, len(templates))
+	for key, text := range templates {
+		rendered, err := renderTemplate(key, text, data)
+		if err != nil {
+			return nil, err
+		}
+		output[key] = rendered
+	}
+	return output, nil
+}
+
+func renderTemplate(name, text string, data Data) ([]byte, error) {
+	tmpl, err := template.New(name).Funcs(templateFuncs).Option("missingkey=error").Parse(text)
+	if err != nil {
+		return nil, fmt.Errorf("parse template %q: %w", name, err)
+	}
+
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&limitedWriter{w: &buf, n: maxTemplateSize}, data); err != nil {
+		return nil, fmt.Errorf("render template %q: %w", name, err)
+	}
+	return buf.Bytes(), nil
+}
+
+// limitedWriter fails writes once more than n bytes would be written to w.
+type limitedWriter struct {
+	w io.Writer
+	n int
+}
+
+func (l *limitedWriter) Write(p []byte) (int, error) {
+	if len(p) > l.n {
+		return 0, fmt.Errorf("output exceeds %d bytes", maxTemplateSize)
+	}
+	l.n -= len(p)
+	return l.w.Write(p)
+}
+
+func toString(v any) string {
+	switch s := v.(type) {
+	case nil:
+		return ""
+	case string:
+		return s
+	case []byte:
+		return string(s)
+	default:
+		return fmt.Sprintf("%v", v)
+	}
+}
+
+func b64dec(v any) (string, error) {
+	b, err := base64.StdEncoding.DecodeString(toString(v))
+	if err != nil {
+		return "", fmt.Errorf("b64dec: %w", err)
+	}
+	return string(b), nil
+}
+
+func toJSON(v any) (string, error) {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return "", fmt.Errorf("toJson: %w", err)
+	}
+	return string(b), nil
+}
+
+func toYaml(v any) (string, error) {
+	b, err := yaml.Marshal(v)
+	if err != nil {
+		return "", fmt.Errorf("toYaml: %w", err)
+	}
+	return strings.TrimSuffix(string(b), "\n"), nil
+}
+
+// defaultValue returns value, or def when value is empty, e.g. {{ index . "port" | default "5432" }}.
+// Missing keys have to be read by index, since templates fail on missing keys referenced as fields.
+func defaultValue(def, value any) any {
+	if value == nil {
+		return def
+	}
+	rv := reflect.ValueOf(value)
+	switch rv.Kind() {
+	case reflect.String, reflect.Map, reflect.Slice, reflect.Array:
+		if rv.Len() == 0 {
+			return def
+		}
+	default:
+		if rv.IsZero() {
+			return def
+		}
+	}
+	return value
+}
+
+func required(msg string, value any) (any, error) {
+	if value == nil || toString(value) == "" {
+		return nil, errors.New(msg)
+	}
+	return value, nil
+}
+
+func join(sep string, values any) string {
+	rv := reflect.ValueOf(values)
+	if rv.Kind() != reflect.Slice && rv.Kind() != reflect.Array {
+		return toString(values)
+	}
+	parts := make([]string, 0, rv.Len())
+	for i := range rv.Len() {
+		parts = append(parts, toString(rv.Index(i).Interface()))
+	}
+	return strings.Join(parts, sep)
+}
+
+func indent(spaces int, s string) string {
+	pad := strings.Repeat(" ", spaces)
+	return pad + strings.ReplaceAll(s, "\n", "\n"+pad)
+}
+
+// keys returns sorted keys of a map, so the output of templates ranging over them is stable.
+func keys(v any) []string {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Map {
+		return nil
+	}
+	output := make([]string, 0, rv.Len())
+	for _, key := range rv.MapKeys() {
+		output = append(output, toString(key.Interface()))
+	}
+	sort.Strings(output)
+	return output
+}
+
+// htpasswd returns an htpasswd line with an unsalted SHA-1 hash of the password, which is supported by nginx and Apache.
+// A salted hash, e.g. bcrypt, would change the rendered Secret on every sync.
+func htpasswd(username, password any) string {
+	//nolint:gosec
+	sum := sha1.Sum([]byte(toString(password)))
+	return toString(username) + ":{SHA}" + base64.StdEncoding.EncodeToString(sum[:])
+}
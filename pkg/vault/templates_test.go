@@ -0,0 +1,40 @@
+package vault
+
+import (
+	"strings"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Templates", func() {
+	data := Data{
+		"db": map[string]any{"username": "app", "password": "secret"},
+	}
+
+	It("should render the data", func() {
+		output, err := renderTemplate("url", `{{ .db.username }}:{{ .db.password }}@{{ index .db "port" | default 5432 }}`, data)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(string(output)).To(Equal("app:secret@5432"))
+	})
+
+	It("should fail on a missing key", func() {
+		_, err := renderTemplate("url", "{{ .db.port }}", data)
+		Expect(err).To(MatchError(ContainSubstring(`map has no entry for key "port"`)))
+	})
+
+	It("should limit the size of the output", func() {
+		_, err := renderTemplate("big", `{{ range $i := .items }}{{ $.chunk }}{{ end }}`, Data{
+			"chunk": strings.Repeat("x", 1024),
+			"items": make([]int, 2048),
+		})
+		Expect(err).To(MatchError(ContainSubstring("output exceeds 1048576 bytes")))
+
+		output, err := renderTemplate("big", `{{ range $i := .items }}{{ $.chunk }}{{ end }}`, Data{
+			"chunk": strings.Repeat("x", 1024),
+			"items": make([]int, 1024),
+		})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(output).To(HaveLen(maxTemplateSize))
+	})
+})
@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"encoding/json"
 	"fmt"
+	"path"
 	"sort"
 	"strings"
 
@@ -15,59 +16,70 @@ const (
 	TypeJSON = "json"
 	TypeEnv  = "env"
 	TypeYaml = "yaml"
+	TypeRaw  = "raw"
 )
 
 type Secrets map[string]any
 
 type PathData struct {
-	BasePath string             `json:"base_path"`
-	Prefix   string             `json:"prefix"`
-	Paths    map[string]Secrets `json:"paths"`
-	Versions map[string]int     `json:"versions"` // KV version (1 or 2) for each path
+	BasePath       string             `json:"base_path"`
+	Prefix         string             `json:"prefix"`
+	Paths          map[string]Secrets `json:"paths"`
+	Versions       map[string]int     `json:"versions"`        // KV version (1 or 2) for each path
+	SecretVersion  int                `json:"secret_version"`  // pinned KV2 secret version, 0 for the latest
+	SecretVersions map[string]int     `json:"secret_versions"` // KV2 secret version read for each path
+	Include        []string           `json:"include"`         // glob patterns of keys to sync, all keys when empty
+	Exclude        []string           `json:"exclude"`         // glob patterns of keys to skip
+	KeyMap         map[string]string  `json:"key_map"`         // renamed keys, Vault key -> target key
+	Optional       bool               `json:"optional"`        // missing or empty paths are skipped
 }
 
-func (pd *PathData) GetRelativePath(path string) string {
-	return strings.ReplaceAll(path, pd.BasePath, "")
-}
-
-// Data is a map of any, because the value can be either Data, Secrets or a string
-type Data map[string]any
-
-func (d Data) AddNode(name string) (Data, error) {
-	// check if name already exists
-	if node, ok := d[name]; ok {
-		switch n := node.(type) {
-		case Data:
-			return n, nil
-		case string:
-			// FIXME: show full path in error
-			return nil, fmt.Errorf("override detected: key %q is already used", name)
-		default:
-			return nil, fmt.Errorf("invalid type received, got: %T", node)
+// SelectSecrets returns secrets read from Vault, which are synced, with renamed keys.
+func (pd *PathData) SelectSecrets(path string, data map[string]any) (Secrets, error) {
+	selected := make(Secrets, len(data))
+	for key, val := range data {
+		name, ok := pd.selectKey(key)
+		if !ok {
+			continue
 		}
+		if _, ok := selected[name]; ok {
+			return nil, fmt.Errorf("%w: key %q is used more than once in %q after renaming", ErrOverride, name, path)
+		}
+		selected[name] = val
 	}
+	return selected, nil
+}
 
-	newNode := make(Data)
-	d[name] = newNode
-
-	return newNode, nil
+func (pd *PathData) selectKey(key string) (string, bool) {
+	if len(pd.Include) > 0 && !matchAny(pd.Include, key) {
+		return "", false
+	}
+	if matchAny(pd.Exclude, key) {
+		return "", false
+	}
+	if renamed, ok := pd.KeyMap[key]; ok {
+		return renamed, true
+	}
+	return key, true
 }
 
-// AddSecrets is used to add multiple key=value pairs to Data
-func (d Data) AddSecrets(secrets Secrets) error {
-	for key, val := range secrets {
-		// check if key already exists
-		if _, ok := d[key]; ok {
-			// FIXME: add a method to Data that calculates full path to here
-			return fmt.Errorf("override detected: key %q is already used", key)
+func matchAny(patterns []string, key string) bool {
+	for _, pattern := range patterns {
+		// patterns are validated by VaultSecretSpec.Validate
+		if ok, _ := path.Match(pattern, key); ok {
+			return true
 		}
-
-		d[key] = val
 	}
+	return false
+}
 
-	return nil
+func (pd *PathData) GetRelativePath(path string) string {
+	return strings.ReplaceAll(path, pd.BasePath, "")
 }
 
+// Data is a map of any, because the value can be either Data, Secrets or a string
+type Data map[string]any
+
 func (d Data) JSON() ([]byte, error) {
 	return json.MarshalIndent(d, "", "  ")
 }
@@ -102,6 +114,73 @@ func (d Data) ENVString(separator string) ([]byte, error) {
 	return b.Bytes(), nil
 }
 
+// Raw returns a key for each secret value, nested keys are joined by separator.
+// String values are kept verbatim, other values are encoded as JSON.
+func (d Data) Raw(separator string) (map[string][]byte, error) {
+	output := make(map[string][]byte)
+	if err := d.addRaw(output, "", separator); err != nil {
+		return nil, err
+	}
+	return output, nil
+}
+
+// RawString returns all raw values, each one preceded by a "==> key <==" header line.
+func (d Data) RawString(separator string) ([]byte, error) {
+	raw, err := d.Raw(separator)
+	if err != nil {
+		return nil, err
+	}
+
+	keys := make([]string, 0, len(raw))
+	for k := range raw {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	var b bytes.Buffer
+	for _, k := range keys {
+		fmt.Fprintf(&b, "==> %s <==\n", k)
+		b.Write(raw[k])
+		b.WriteString("\n")
+	}
+
+	return b.Bytes(), nil
+}
+
+func (d Data) addRaw(output map[string][]byte, prefix, separator string) error {
+	for key, val := range d {
+		if prefix != "" {
+			key = prefix + separator + key
+		}
+
+		if node, ok := val.(Data); ok {
+			if err := node.addRaw(output, key, separator); err != nil {
+				return err
+			}
+			continue
+		}
+
+		if _, ok := output[key]; ok {
+			return fmt.Errorf("%w: key %q is already used", ErrOverride, key)
+		}
+
+		switch v := val.(type) {
+		case string:
+			output[key] = []byte(v)
+		case json.Number:
+			output[key] = []byte(v.String())
+		default:
+			b, err := json.Marshal(v)
+			if err != nil {
+				return fmt.Errorf("failed to encode key %q: %w", key, err)
+			}
+			output[key] = b
+		}
+	}
+
+	return nil
+}
+
 func (d Data) createENV(separator string) (map[string]any, error) {
 	b, err := d.JSON()
 
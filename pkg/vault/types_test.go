@@ -0,0 +1,29 @@
+package vault
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Raw data", func() {
+	It("should join keys of nested data with the separator", func() {
+		raw, err := Data{
+			"db":   Data{"port": 5432, "host": "db"},
+			"cert": "-----BEGIN CERTIFICATE-----\n",
+		}.Raw("_")
+		Expect(err).ToNot(HaveOccurred())
+		Expect(raw).To(Equal(map[string][]byte{
+			"db_port": []byte("5432"),
+			"db_host": []byte("db"),
+			"cert":    []byte("-----BEGIN CERTIFICATE-----\n"),
+		}))
+	})
+
+	It("should fail with ErrOverride when keys collide after joining", func() {
+		_, err := Data{
+			"db":      Data{"host": "db"},
+			"db_host": "other",
+		}.Raw("_")
+		Expect(err).To(MatchError(ErrOverride))
+	})
+})
@@ -5,12 +5,13 @@ import (
 	"errors"
 	"fmt"
 	"io"
+	"maps"
+	"sort"
 	"strings"
 	"sync"
 	"time"
 
 	"github.com/go-logr/logr"
-	"github.com/hashicorp/go-retryablehttp"
 	vaultApi "github.com/hashicorp/vault/api"
 	"golang.org/x/sync/errgroup"
 
@@ -18,36 +19,62 @@ import (
 )
 
 type Reader struct {
-	client *vaultApi.Client
-	secret *v1.VaultSecret
-	paths  []PathData
-	data   Data
-	cfg    *AppConfig
-	log    logr.Logger
+	client  *vaultApi.Client
+	secret  *v1.VaultSecret
+	paths   []PathData
+	data    Data
+	cfg     *AppConfig
+	log     logr.Logger
+	limiter *RequestLimiter
 }
 
-func NewReader(tokener Tokener, secret *v1.VaultSecret, logger logr.Logger, cfg *AppConfig) (*Reader, error) {
-	client, err := vaultApi.NewClient(&vaultApi.Config{
-		Address:    secret.Spec.Addr,
-		MaxRetries: cfg.ClientMaxRetries,
-		Timeout:    cfg.ClientTimeout,
-		Backoff:    retryablehttp.LinearJitterBackoff,
-	})
+// ReaderOption customizes the Reader created by NewReader.
+type ReaderOption func(*readerOptions)
+
+type readerOptions struct {
+	caCert  []byte
+	limiter *RequestLimiter
+}
+
+// WithCACert sets a PEM-encoded CA bundle used to verify the Vault server certificate.
+func WithCACert(caCert []byte) ReaderOption {
+	return func(o *readerOptions) {
+		o.caCert = caCert
+	}
+}
 
+// WithRequestLimiter limits Vault requests of the Reader by a limiter shared with other Readers.
+func WithRequestLimiter(limiter *RequestLimiter) ReaderOption {
+	return func(o *readerOptions) {
+		o.limiter = limiter
+	}
+}
+
+func NewReader(tokener Tokener, secret *v1.VaultSecret, logger logr.Logger, cfg *AppConfig, opts ...ReaderOption) (*Reader, error) {
+	var options readerOptions
+	for _, opt := range opts {
+		opt(&options)
+	}
+
+	client, err := NewClientForAddr(*cfg, secret.Spec.Addr, options.caCert)
 	if err != nil {
 		return nil, fmt.Errorf("could not create Vault client: %w", err)
 	}
+	if secret.Spec.Namespace != "" {
+		client.SetNamespace(secret.Spec.Namespace)
+	}
 
 	r := Reader{
-		client: client,
-		secret: secret,
-		cfg:    cfg,
-		log:    logger,
+		client:  client,
+		secret:  secret,
+		cfg:     cfg,
+		log:     logger,
+		limiter: options.limiter,
 	}
 
 	token, err := tokener.Token()
 	if err != nil {
-		return nil, fmt.Errorf("retrieve token: %w", err)
+		return nil, fmt.Errorf("retrieve token: %w: %w", ErrAuth, err)
 	}
 	client.SetToken(token)
 
@@ -72,6 +99,23 @@ func (r *Reader) GetPathVersions() map[string]int {
 	return versions
 }
 
+// GetPathStatuses returns all paths read by ReadData with the version of the secret read from them.
+func (r *Reader) GetPathStatuses() []v1.VaultSecretPathStatus {
+	var statuses []v1.VaultSecretPathStatus
+	for _, pathData := range r.paths {
+		for path := range pathData.Versions {
+			statuses = append(statuses, v1.VaultSecretPathStatus{
+				Path:    path,
+				Version: pathData.SecretVersions[path],
+			})
+		}
+	}
+	sort.Slice(statuses, func(i, j int) bool {
+		return statuses[i].Path < statuses[j].Path
+	})
+	return statuses
+}
+
 func (r *Reader) ReadData(ctx context.Context) error {
 	if err := r.getAbsolutePaths(ctx); err != nil {
 		return fmt.Errorf("failed to get paths from vault: %w", err)
@@ -103,6 +147,8 @@ func (r *Reader) WriteData(w io.Writer, format string) error {
 		b, err = r.data.ENVString(r.secret.Spec.GetSeparator())
 	case TypeYaml:
 		b, err = r.data.Yaml()
+	case TypeRaw:
+		b, err = r.data.RawString(r.secret.Spec.GetSeparator())
 	default:
 		return fmt.Errorf("unsupported output format: %s", format)
 	}
@@ -119,10 +165,32 @@ func (r *Reader) WriteData(w io.Writer, format string) error {
 	return nil
 }
 
+// WriteTemplate renders the template with the key from spec.templates.
+func (r *Reader) WriteTemplate(w io.Writer, key string) error {
+	text, ok := r.secret.Spec.Templates[key]
+	if !ok {
+		return fmt.Errorf("template %q is not defined in spec.templates", key)
+	}
+
+	b, err := renderTemplate(key, text, r.data)
+	if err != nil {
+		return err
+	}
+
+	_, err = w.Write(b)
+	if err != nil {
+		return fmt.Errorf("could not write: %w", err)
+	}
+
+	return nil
+}
+
 // getAbsolutePaths populates []PathData with absolute paths to Vault secrets.
 // In the case of "secret/recursive/path/*", it will recursively call Vault and
 // find all child Secrets with their absolute paths.
 func (r *Reader) getAbsolutePaths(ctx context.Context) error {
+	operatorLimits := newPathLimits(r.cfg.MaxPathDepth, r.cfg.MaxResolvedPaths)
+	resolved := 0
 	for _, path := range r.secret.Spec.Paths {
 		paths := make(map[string]Secrets)
 		var cleanedPath string
@@ -133,12 +201,38 @@ func (r *Reader) getAbsolutePaths(ctx context.Context) error {
 			path.Path = path.Path[1:]
 		}
 
-		// if last char is "*", then recursively call Vault until all subPaths are found
-		if path.Path[len(path.Path)-1] == '*' {
+		optional := r.cfg.DefaultPathsOptional
+		if path.Optional != nil {
+			optional = *path.Optional
+		}
+
+		// a path can only lower the operator limit of depth
+		limits := operatorLimits
+		if path.MaxDepth > 0 && path.MaxDepth < limits.maxDepth {
+			limits.maxDepth = path.MaxDepth
+		}
+
+		if isPattern(path.Path) {
+			// glob patterns in the middle of the path are resolved by listing Vault
+			cleanedPath = patternBase(path.Path)
+
+			subPaths, err := r.expandPattern(ctx, path.Path, limits)
+			if err != nil {
+				return err
+			}
+			if len(subPaths) == 0 && !optional {
+				return fmt.Errorf("%w: %s", ErrMissingPaths, path.Path)
+			}
+
+			for _, subPath := range subPaths {
+				paths[subPath] = make(Secrets)
+			}
+		} else if path.Path[len(path.Path)-1] == '*' {
+			// if last char is "*", then recursively call Vault until all subPaths are found
 			// remove "*" before calling Vault
 			cleanedPath = path.Path[0 : len(path.Path)-1]
 
-			subPaths, err := r.getPathsRecursive(ctx, cleanedPath)
+			subPaths, err := r.getPathsRecursive(ctx, cleanedPath, limits)
 			if err != nil {
 				return err
 			}
@@ -152,11 +246,22 @@ func (r *Reader) getAbsolutePaths(ctx context.Context) error {
 			paths[path.Path] = make(Secrets)
 		}
 
+		resolved += len(paths)
+		if resolved > operatorLimits.maxPaths {
+			return fmt.Errorf("%w: paths resolve to more than %d Vault paths", ErrPathLimit, operatorLimits.maxPaths)
+		}
+
 		r.paths = append(r.paths, PathData{
-			BasePath: cleanedPath,
-			Prefix:   path.Prefix,
-			Paths:    paths,
-			Versions: make(map[string]int), // Initialize versions map
+			BasePath:       cleanedPath,
+			Prefix:         path.Prefix,
+			Paths:          paths,
+			Versions:       make(map[string]int), // Initialize versions map
+			SecretVersion:  path.Version,
+			SecretVersions: make(map[string]int),
+			Include:        path.Include,
+			Exclude:        path.Exclude,
+			KeyMap:         path.KeyMap,
+			Optional:       optional,
 		})
 	}
 
@@ -164,9 +269,10 @@ func (r *Reader) getAbsolutePaths(ctx context.Context) error {
 }
 
 // Function iterate over reader's paths, merge secrets into one piece.
-// If some path doesn't exist, it's skipped and function finish with
+// If some optional path doesn't exist, it's skipped and function finish with
 // no error. It's more reliable than let function crash the
-// reconciliation loop.
+// reconciliation loop. Missing paths, which aren't optional, are
+// reported together in ErrMissingPaths.
 //
 // This function don't take list of paths and don't return the secrets.
 // Instead of this, the function works with reader's state. Don't know the
@@ -181,19 +287,29 @@ func (r *Reader) readSecretsFromPaths(ctx context.Context) error {
 		Client:          r.client,
 		log:             r.log,
 		reconcilePeriod: reconcilePeriod,
+		limiter:         r.limiter,
 	}
 
 	wg, gCtx := errgroup.WithContext(ctx)
 	wg.SetLimit(20)
-	var mx sync.Mutex
+	var (
+		mx      sync.Mutex
+		missing []string
+	)
 	for i := range r.paths {
 		pathData := &r.paths[i] // Get pointer to modify in place
 		for absolutePath, secrets := range pathData.Paths {
 			absolutePath := absolutePath
 			secrets := secrets
 			wg.Go(func() error {
-				secretsData, version, err := pathReader.Read(gCtx, absolutePath)
+				result, err := pathReader.Read(gCtx, absolutePath, pathData.SecretVersion)
 				if err != nil {
+					if !pathData.Optional && (errors.Is(err, ErrNotFound) || errors.Is(err, ErrEmpty)) {
+						mx.Lock()
+						missing = append(missing, absolutePath)
+						mx.Unlock()
+						return nil
+					}
 					if errors.Is(err, ErrNotFound) {
 						// make a log entry and skip the broken path
 						r.log.Error(err, absolutePath)
@@ -204,16 +320,15 @@ func (r *Reader) readSecretsFromPaths(ctx context.Context) error {
 					}
 					return err
 				}
+				selected, err := pathData.SelectSecrets(absolutePath, result.Data)
+				if err != nil {
+					return err
+				}
 				// Protect concurrent map writes
 				mx.Lock()
-				pathData.Versions[absolutePath] = version
-				for k, v := range secretsData {
-					_, ok := secrets[k]
-					if ok {
-						r.log.Error(fmt.Errorf("duplicate secret key: %v", k), "overriding secret key", "key", k)
-					}
-					secrets[k] = v
-				}
+				pathData.Versions[absolutePath] = result.KVVersion
+				pathData.SecretVersions[absolutePath] = result.Version
+				maps.Copy(secrets, selected)
 				mx.Unlock()
 				return nil
 			})
@@ -223,85 +338,41 @@ func (r *Reader) readSecretsFromPaths(ctx context.Context) error {
 		return err
 	}
 
+	if len(missing) > 0 {
+		sort.Strings(missing)
+		return fmt.Errorf("%w: %s", ErrMissingPaths, strings.Join(missing, ", "))
+	}
+
 	return nil
 }
 
-func (r *Reader) getPathsRecursive(ctx context.Context, path string) ([]string, error) {
-	mountPath, version, err := kvPreflightVersionRequest(ctx, r.client, path)
-
+// getPathsRecursive returns all secrets under path, sub-paths are listed level by level up to limits.maxDepth.
+func (r *Reader) getPathsRecursive(ctx context.Context, path string, limits pathLimits) ([]string, error) {
+	_, paths, err := r.walkDirs(ctx, []string{path}, limits)
 	if err != nil {
 		return nil, err
 	}
 
-	if version != 1 && version != 2 {
-		return nil, fmt.Errorf("unsupported engine for recursion, expected 1 or 2, got %d", version)
-	}
-	apiPath := path
-	if version == 2 {
-		apiPath = addPrefixToKVPath(path, mountPath, "metadata")
-	}
-
-	secretValues, err := r.client.Logical().ListWithContext(ctx, apiPath)
-	if err != nil {
-		return nil, fmt.Errorf("could not read Vault path %q: %w", apiPath, err)
-	}
-
-	if secretValues == nil {
+	if len(paths) == 0 {
 		return nil, fmt.Errorf("no value found in path: %q", path)
 	}
 
-	keys, ok := secretValues.Data["keys"].([]any)
-	if !ok {
-		return nil, fmt.Errorf("cannot cast keys to slice of interfaces at path: %q", apiPath)
-	}
-
-	var paths []string
-	wg, gCtx := errgroup.WithContext(ctx)
-	var mx sync.Mutex
-	appendPaths := func(subPaths ...string) {
-		mx.Lock()
-		defer mx.Unlock()
-		paths = append(paths, subPaths...)
-	}
-	wg.SetLimit(10)
-	for _, k := range keys {
-		key, ok := k.(string)
-		if !ok {
-			return nil, fmt.Errorf("cannot cast keys to string at path: %q", apiPath)
-		}
-
-		fullPath := path + key
-		wg.Go(func() error {
-			// check if current key is a directory
-			if key[len(key)-1] == '/' {
-				// and fetch all subPaths
-				subPaths, err := r.getPathsRecursive(gCtx, fullPath)
-				if err != nil {
-					return err
-				}
-				// and append them to the list
-				appendPaths(subPaths...)
-			} else {
-				// else, just add it to the list
-				appendPaths(fullPath)
-			}
-			return nil
-		})
-	}
-	if err := wg.Wait(); err != nil {
-		return nil, err
-	}
-
 	return paths, nil
 }
 
 func (r *Reader) createVaultData() error {
-	rootNode := make(Data)
+	m := newMerger(r.secret.Spec.GetMergeStrategy())
 
 	for _, pathData := range r.paths {
-		currentNode := rootNode
+		// sub-paths of recursive paths are merged in alphabetical order
+		absolutePaths := make([]string, 0, len(pathData.Paths))
+		for path := range pathData.Paths {
+			absolutePaths = append(absolutePaths, path)
+		}
+		sort.Strings(absolutePaths)
 
-		for path, secrets := range pathData.Paths {
+		for _, path := range absolutePaths {
+			secrets := pathData.Paths[path]
 			// get relative path (abs path - base path) with prefix
 			relativePath := pathData.GetRelativePath(path)
 			relativePathWithPrefix := pathData.Prefix + relativePath
@@ -309,7 +380,7 @@ func (r *Reader) createVaultData() error {
 
 			// if length is 0, it means we have actual secrets
 			if len(relativePathWithPrefix) == 0 {
-				if err := currentNode.AddSecrets(secrets); err != nil {
+				if err := m.add(path, nil, secrets); err != nil {
 					return err
 				}
 
@@ -327,31 +398,19 @@ func (r *Reader) createVaultData() error {
 				pathSegments = pathSegments[:len(pathSegments)-1]
 			}
 
-			// previousNode always points to the parent node
-			previousNode := currentNode
-
-			for _, pathSegment := range pathSegments {
-				var err error
-
-				previousNode, err = previousNode.AddNode(pathSegment)
-				if err != nil {
-					return err
-				}
-			}
-
 			newSecrets := make(Secrets, len(secrets))
 
 			for k, v := range secrets {
 				newSecrets[secretKeyPrefix+k] = v
 			}
 
-			if err := previousNode.AddSecrets(newSecrets); err != nil {
+			if err := m.add(path, pathSegments, newSecrets); err != nil {
 				return err
 			}
 		}
 	}
 
-	r.data = rootNode
+	r.data = m.root
 
 	return nil
 }
@@ -0,0 +1,47 @@
+package vault
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	v1 "github.com/kiwicom/k8s-vault-operator/api/v1"
+)
+
+var _ = Describe("Reader", func() {
+	var server *fakeVault
+
+	BeforeEach(func() {
+		server = newFakeVault(map[string]map[string]any{
+			"secret/team/app/db": {"password": "secret"},
+		})
+		DeferCleanup(server.Close)
+	})
+
+	It("should send the namespace with every request", func() {
+		reader := newTestReader(server.URL, v1.VaultSecretSpec{
+			Namespace: "team-ns",
+			Paths:     []v1.VaultSecretPath{{Path: "secret/team/*"}},
+		})
+		Expect(reader.ReadData(context.Background())).To(Succeed())
+		Expect(reader.GetData()).To(HaveKeyWithValue("app", HaveKeyWithValue("db", HaveKeyWithValue("password", "secret"))))
+
+		for _, apiPath := range []string{"sys/internal/ui/mounts/secret/team/app/db", "secret/metadata/team", "secret/data/team/app/db"} {
+			namespace, ok := server.namespace(apiPath)
+			Expect(ok).To(BeTrue(), apiPath)
+			Expect(namespace).To(Equal("team-ns"), apiPath)
+		}
+	})
+
+	It("should not send a namespace when it isn't set", func() {
+		reader := newTestReader(server.URL, v1.VaultSecretSpec{
+			Paths: []v1.VaultSecretPath{{Path: "secret/team/app/db"}},
+		})
+		Expect(reader.ReadData(context.Background())).To(Succeed())
+
+		namespace, ok := server.namespace("secret/data/team/app/db")
+		Expect(ok).To(BeTrue())
+		Expect(namespace).To(BeEmpty())
+	})
+})